@@ -2,12 +2,14 @@ package main_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"math"
 	"testing"
 
 	mainapp "github.com/cardil/ghet/cmd/ght"
 	"github.com/cardil/ghet/internal/ght"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wavesoftware/go-commandline"
 )
 
@@ -31,3 +33,31 @@ func TestMainFunc(t *testing.T) {
 	assert.Contains(t, out, "Gʰet artifacts from GitHub releases")
 	assert.Equal(t, retcode, math.MinInt64)
 }
+
+func TestStructuredOutput(t *testing.T) {
+	retcode := math.MinInt64
+	defer func() {
+		ght.Options = nil
+	}()
+	var buf bytes.Buffer
+	ght.Options = []commandline.Option{
+		commandline.WithExit(func(code int) {
+			retcode = code
+		}),
+		commandline.WithOutput(&buf),
+		commandline.WithArgs("install", "--output", "json", "not-a-repo"),
+	}
+
+	mainapp.Main()
+
+	var report struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "repository_not_given", report.Error.Code)
+	assert.Contains(t, report.Error.Message, "not-a-repo")
+	assert.NotEqual(t, 0, retcode)
+}
@@ -6,6 +6,7 @@ require (
 	dario.cat/mergo v1.0.0
 	emperror.dev/errors v0.8.1
 	github.com/1set/gut v0.0.0-20201117175203-a82363231997
+	github.com/Masterminds/semver/v3 v3.3.0
 	github.com/google/go-github/v48 v48.2.0
 	github.com/gookit/color v1.5.4
 	github.com/kirsle/configdir v0.0.0-20170128060238-e45d2f54772f
@@ -14,7 +15,7 @@ require (
 	github.com/stretchr/testify v1.9.0
 	github.com/u-root/u-root v0.14.0
 	github.com/wavesoftware/go-commandline v1.0.0
-	golang.org/x/oauth2 v0.23.0
+	golang.org/x/sync v0.9.0
 	knative.dev/client/pkg v0.0.0-20241128155143-441372aea16b
 	sigs.k8s.io/yaml v1.4.0
 )
@@ -71,7 +72,6 @@ require (
 	go4.org v0.0.0-20230225012048-214862532bf5 // indirect
 	golang.org/x/crypto v0.29.0 // indirect
 	golang.org/x/net v0.31.0 // indirect
-	golang.org/x/sync v0.9.0 // indirect
 	golang.org/x/sys v0.27.0 // indirect
 	golang.org/x/term v0.26.0 // indirect
 	golang.org/x/text v0.20.0 // indirect
@@ -2,6 +2,7 @@ package ght
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"emperror.dev/errors"
@@ -13,6 +14,9 @@ import (
 	"knative.dev/client/pkg/output/logging"
 )
 
+// errInvalidArgs is returned when the command is given wrong arguments.
+var errInvalidArgs = errors.New("invalid arguments")
+
 // Options to override the commandline for testing purposes.
 var Options []commandline.Option //nolint:gochecknoglobals
 
@@ -31,11 +35,23 @@ func (a *App) Command() *cobra.Command {
 		versionCmd,
 		installCmd,
 		removeCmd,
+		useCmd,
+		versionsCmd,
 		listCmd,
+		upgradeCmd,
+		outdatedCmd,
+		syncCmd,
+		shimCmd,
+		execCmd,
 		downloadCmd,
+		explainCmd,
 	}
 	for _, cmd := range cmds {
-		c.AddCommand(cmd(&a.Args))
+		sub := cmd(&a.Args)
+		sub.Args = a.validateArgs(sub.Args)
+		sub.PersistentPreRunE = a.reportErrors(sub.PersistentPreRunE)
+		sub.RunE = a.reportErrors(sub.RunE)
+		c.AddCommand(sub)
 	}
 	c.SetOut(os.Stdout)
 	c.SetContext(logging.EnsureLogger(
@@ -58,10 +74,57 @@ func postRunE(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+// validateArgs checks the output format, and then the arguments of the
+// command, as cobra validates them before running any of the hooks. An
+// unsupported format is reported in JSON, as the requested one can't be used.
+func (a *App) validateArgs(fn cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := outputFormat(a.Output).validate(); err != nil {
+			cmd.SilenceErrors = true
+			if perr := outputJSON.print(cmd.OutOrStdout(), newErrorReport(err)); perr != nil {
+				return errors.Combine(err, perr)
+			}
+			return err
+		}
+		if fn == nil {
+			return nil
+		}
+		return a.reportErrors(func(cmd *cobra.Command, args []string) error {
+			if err := fn(cmd, args); err != nil {
+				return errors.WithStack(fmt.Errorf("%w: %v", errInvalidArgs, err))
+			}
+			return nil
+		})(cmd, args)
+	}
+}
+
+// reportErrors prints the errors of the command as structured reports, in
+// place of the usual messages, if a structured output is requested.
+func (a *App) reportErrors(
+	fn func(cmd *cobra.Command, args []string) error,
+) func(cmd *cobra.Command, args []string) error {
+	if fn == nil {
+		return nil
+	}
+	return func(cmd *cobra.Command, args []string) error {
+		err := fn(cmd, args)
+		format := outputFormat(a.Output)
+		if err == nil || !format.structured() || reportedInResults(err) {
+			return err
+		}
+		cmd.SilenceErrors = true
+		if perr := format.print(cmd.OutOrStdout(), newErrorReport(err)); perr != nil {
+			return errors.Combine(err, perr)
+		}
+		return err
+	}
+}
+
 func handle(args *Args, fn func(ctx context.Context) error) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		ctx := cmd.Context()
 		ctx = output.WithContext(ctx, cmd)
+		ctx = withResults(ctx, outputFormat(args.Output))
 		cfg, err := config.Load(ctx, args.ConfigPath)
 		if err != nil {
 			return err
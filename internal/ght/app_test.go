@@ -0,0 +1,94 @@
+package ght
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/upgrade"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestReportErrors(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name       string
+		err        error
+		wantReport bool
+	}{{
+		name:       "error",
+		err:        errors.WithStack(download.ErrNoAssetFound),
+		wantReport: true,
+	}, {
+		name: "updates available",
+		err:  errors.WithStack(fmt.Errorf("%w: 1", upgrade.ErrUpdatesAvailable)),
+	}}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			app := &App{Args: Args{Output: string(outputJSON)}}
+			cmd := &cobra.Command{}
+			cmd.SetOut(&buf)
+			run := app.reportErrors(func(cmd *cobra.Command, _ []string) error {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), `[{"latest": "v1.1.0"}]`)
+				return tc.err
+			})
+
+			err := run(cmd, nil)
+
+			require.ErrorIs(t, err, tc.err)
+			dec := json.NewDecoder(&buf)
+			var results []map[string]string
+			require.NoError(t, dec.Decode(&results))
+			assert.Equal(t, tc.wantReport, dec.More(),
+				"the error report follows the results: %q", buf.String())
+		})
+	}
+}
+
+func TestReportArgsErrors(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		args     []string
+		wantCode string
+	}{{
+		name:     "missing argument",
+		args:     []string{"-o", "json", "explain"},
+		wantCode: "invalid_arguments",
+	}, {
+		name:     "unexpected argument",
+		args:     []string{"-o", "yaml", "list", "extra"},
+		wantCode: "invalid_arguments",
+	}, {
+		name:     "unsupported output",
+		args:     []string{"-o", "xml", "list"},
+		wantCode: "unsupported_output",
+	}}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			cmd := new(App).Command()
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			err := cmd.Execute()
+
+			require.Error(t, err)
+			var report errorReport
+			require.NoError(t, yaml.Unmarshal(buf.Bytes(), &report), buf.String())
+			assert.Equal(t, tc.wantCode, report.Error.Code)
+		})
+	}
+}
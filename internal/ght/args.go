@@ -10,6 +10,7 @@ import (
 
 type Args struct {
 	ConfigPath string
+	Output     string
 }
 
 func (a Args) Defaults(ctx context.Context) Args {
@@ -26,4 +27,6 @@ func (a *App) setFlags(c *cobra.Command) {
 	fl := c.PersistentFlags()
 	fl.StringVarP(&a.ConfigPath, "config", "c",
 		defs.ConfigPath, "path to configuration file")
+	fl.StringVarP(&a.Output, "output", "o", "",
+		"an output format, one of: json, yaml")
 }
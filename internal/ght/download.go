@@ -20,7 +20,8 @@ func downloadCmd(args *Args) *cobra.Command {
 		Short:             "Download an artifact from GitHub release",
 		PersistentPreRunE: da.valiadate(),
 		RunE:              handle(args, downloadAction(da)),
-		Example:           "\n * ght download -v 0.1.0 -t /tmp cardil/ghet",
+		Example: "\n * ght download -v 0.1.0 -d /tmp cardil/ghet" +
+			"\n * ght download --dry-run cardil/ghet",
 	}
 	da.setFlags(c)
 	return c
@@ -36,7 +37,25 @@ func (da *downloadArgs) setFlags(c *cobra.Command) {
 
 func downloadAction(da *downloadArgs) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		return download.Action(ctx, da.parse(ctx))
+		args := da.parse(ctx)
+		if da.dryRun {
+			previews, err := previewPlans(ctx, []download.Args{args})
+			if err != nil {
+				return err
+			}
+			if res := resultsFrom(ctx); res.format.structured() {
+				return res.print(previews[0])
+			}
+			return printPreviews(ctx, previews)
+		}
+		result, err := download.Action(ctx, args)
+		if err != nil {
+			return err
+		}
+		if res := resultsFrom(ctx); res.format.structured() {
+			return res.print(result)
+		}
+		return nil
 	}
 }
 
@@ -44,5 +63,6 @@ func (da *downloadArgs) parse(ctx context.Context) download.Args {
 	return download.Args{
 		Args:        da.installArgs.parse(ctx),
 		Destination: da.destination,
+		Parallel:    da.parallel,
 	}
 }
@@ -0,0 +1,90 @@
+package ght
+
+import (
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/manifest"
+	"github.com/cardil/ghet/pkg/ghet/remove"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/cardil/ghet/pkg/ghet/upgrade"
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/retry"
+)
+
+// unknownErrorCode is reported for the errors without a code of their own.
+const unknownErrorCode = "unknown"
+
+// errorCodes are the stable codes of the known errors. The more specific
+// errors are listed first, as the first matching one wins.
+var errorCodes = []struct { //nolint:gochecknoglobals
+	err  error
+	code string
+}{
+	{download.ErrNoAssetFound, "no_asset_found"},
+	{download.ErrDraftRelease, "draft_release"},
+	{download.ErrNoMatchingRelease, "no_matching_release"},
+	{download.ErrNoVersionedRelease, "no_versioned_release"},
+	{download.ErrChecksumMismatch, "checksum_mismatch"},
+	{download.ErrDigestMismatch, "digest_mismatch"},
+	{download.ErrNoChecksum, "no_checksum"},
+	{download.ErrTooManyChecksums, "too_many_checksums"},
+	{download.ErrUnknownChecksumAlgorithm, "unknown_checksum_algorithm"},
+	{download.ErrInvalidChecksumLine, "invalid_checksum_line"},
+	{download.ErrNotVerifiedAssets, "not_verified_assets"},
+	{state.ErrNotInstalled, "not_installed"},
+	{state.ErrAmbiguousTool, "ambiguous_tool"},
+	{state.ErrLocked, "database_locked"},
+	{state.ErrInvalidDatabase, "invalid_database"},
+	{remove.ErrModifiedBinary, "modified_binary"},
+	{upgrade.ErrUpdatesAvailable, "updates_available"},
+	{manifest.ErrOutdatedLock, "outdated_lock"},
+	{manifest.ErrInvalidLock, "invalid_lock"},
+	{manifest.ErrInvalidManifest, "invalid_manifest"},
+	{config.ErrInvalidConfigFile, "invalid_config"},
+	{provider.ErrInvalidTemplate, "invalid_template"},
+	{provider.ErrUnsupportedSite, "unsupported_site"},
+	{provider.ErrNotFound, "not_found"},
+	{provider.ErrUnexpectedResponse, "unexpected_response"},
+	{retry.ErrRateLimited, "rate_limited"},
+	{github.ErrInvalidVersion, "invalid_version"},
+	{errRepoNotGiven, "repository_not_given"},
+	{errVersionNotGiven, "version_not_given"},
+	{errInvalidArgs, "invalid_arguments"},
+	{errUnsupportedOutput, "unsupported_output"},
+	{download.ErrUnexpected, "unexpected"},
+}
+
+type errorReport struct {
+	Error reportedError `json:"error"`
+}
+
+type reportedError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorReport(err error) errorReport {
+	return errorReport{Error: reportedError{
+		Code:    errorCode(err),
+		Message: err.Error(),
+	}}
+}
+
+// reportedInResults checks if the error is told by the printed results
+// already, like the updates available, so no error report is printed after
+// them. The exit code still carries the error.
+func reportedInResults(err error) bool {
+	return errors.Is(err, upgrade.ErrUpdatesAvailable)
+}
+
+// errorCode returns the stable code of the error.
+func errorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return unknownErrorCode
+}
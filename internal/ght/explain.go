@@ -0,0 +1,77 @@
+package ght
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/spf13/cobra"
+)
+
+func explainCmd(args *Args) *cobra.Command {
+	ea := &installCmdArgs{}
+	c := &cobra.Command{
+		Use:   "explain [flags] <owner>/<repo>[@version][!!binary]",
+		Short: "Explain how the assets of the release are matched",
+		Long: "Explain how the assets of the release are matched. Lists every " +
+			"asset with the verdicts of the basename, architecture, operating " +
+			"system, and checksum matchers, and its score, and tells which assets " +
+			"would be downloaded, and which were dropped in favor of others.",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: ea.validate(),
+		RunE:              handle(args, explainAction(ea)),
+		Example: "\n * ght explain derailed/k9s" +
+			"\n * ght explain knative-sandbox/kn-plugin-event@v1.11.0!!kn-event",
+	}
+	defs := ea.defaults()
+	fl := c.Flags()
+	fl.StringVar(&ea.site, "site",
+		defs.site, "a site to download from")
+	fl.StringVar(&ea.checksums, "checksums", defs.checksums,
+		"a checksums file name")
+	ea.setPreReleaseFlag(c)
+	return c
+}
+
+func explainAction(ea *installCmdArgs) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ex, err := download.Explain(ctx, ea.parse(ctx)[0])
+		if err != nil {
+			return err
+		}
+		res := resultsFrom(ctx)
+		if res.format.structured() {
+			return res.print(ex)
+		}
+		_, _ = fmt.Fprintf(res.out, "%s %s, matching %s for %s/%s\n",
+			ex.Repository, ex.Tag, ex.BaseName,
+			ex.OperatingSystem, ex.Architecture)
+		tw := newTableWriter(res.out)
+		_, _ = fmt.Fprintln(tw, "ASSET\tCATEGORY\tBASENAME\tARCH\tOS\tCHECKSUM\tPACKAGE\tSCORE\tRESULT")
+		for _, a := range ex.Assets {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				a.Name, a.Category, yesNo(a.BaseName), yesNo(a.Architecture),
+				yesNo(a.OperatingSystem), yesNo(a.Checksum),
+				yesNo(a.PackageManager), a.Score, verdict(a))
+		}
+		return errors.WithStack(tw.Flush())
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func verdict(a download.ExplainedAsset) string {
+	switch {
+	case a.Chosen:
+		return "chosen"
+	case a.Dropped:
+		return "dropped"
+	}
+	return "rejected"
+}
@@ -3,11 +3,16 @@ package ght
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path"
 	"regexp"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/cardil/ghet/pkg/config"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
 	"github.com/cardil/ghet/pkg/ghet/install"
+	"github.com/cardil/ghet/pkg/ghet/installer"
 	"github.com/cardil/ghet/pkg/github"
 	"github.com/spf13/cobra"
 )
@@ -17,14 +22,129 @@ var (
 	reporRe         = regexp.MustCompile(`^([a-zA-Z0-9-]+)/([a-zA-Z0-9-]+)$`)
 )
 
-func installCmd(_ *Args) *cobra.Command {
-	return &cobra.Command{
-		Use:   "install",
+func installCmd(args *Args) *cobra.Command {
+	ia := &installCmdArgs{}
+	c := &cobra.Command{
+		Use:   "install [flags] <owner>/<repo>[@version][::archive][!!binary]...",
 		Short: "Install an artifact from GitHub release",
-		Run: func(cmd *cobra.Command, _ []string) {
-			cmd.Println("install")
-		},
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  handle(args, installAction(ia)),
+		Example: "\n * ght install cardil/ghet@v0.3.0" +
+			"\n * ght install -b /usr/local/bin derailed/k9s sharkdp/diskus" +
+			"\n * ght install knative-sandbox/kn-plugin-event!!kn-event" +
+			"\n * ght install 'cli/cli@^2.40!!gh' 'derailed/k9s@>=0.30,<0.32'" +
+			"\n * ght install --dry-run derailed/k9s",
+		PersistentPreRunE: ia.validate(),
+	}
+	ia.setFlags(c)
+	return c
+}
+
+type installCmdArgs struct {
+	installArgs
+	binDir string
+	specs  []string
+}
+
+func (ia *installCmdArgs) setFlags(c *cobra.Command) {
+	defs := ia.defaults()
+	fl := c.Flags()
+	fl.StringVarP(&ia.binDir, "bin-dir", "b", "",
+		"a directory to install binaries to (default $"+
+			configdir.BinDirEnvName+" or ~/.local/bin)")
+	fl.StringVar(&ia.site, "site",
+		defs.site, "a site to download from")
+	fl.StringVar(&ia.checksums, "checksums", defs.checksums,
+		"a checksums file name")
+	fl.BoolVar(&ia.multipleBinaries, "multiple-binaries", defs.multipleBinaries,
+		"if set, will extract all binaries from the archive")
+	fl.BoolVar(&ia.verifyInArchive, "verify-in-archive", defs.verifyInArchive,
+		"if set, will verify the checksums against the binaries in the archive")
+	ia.setPreReleaseFlag(c)
+	ia.setParallelFlag(c)
+	ia.setDryRunFlag(c)
+}
+
+func (ia *installArgs) setDryRunFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&ia.dryRun, "dry-run", false,
+		"if set, will only show the assets that would be downloaded, "+
+			"and where the binaries would be placed")
+}
+
+func (ia *installArgs) setParallelFlag(c *cobra.Command) {
+	c.Flags().IntVar(&ia.parallel, "parallel", download.DefaultParallel,
+		"a number of assets to download at once")
+}
+
+func (ia *installArgs) setPreReleaseFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&ia.preRelease, "prerelease", ia.defaults().preRelease,
+		"if set, will consider pre-releases when resolving the latest release "+
+			"or a version constraint")
+}
+
+func (ia *installCmdArgs) validate() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		for _, spec := range args {
+			a := install.Parse(spec)
+			if a.Owner == "" || a.Repo == "" {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("%w: %q", errRepoNotGiven, spec)
+			}
+		}
+		ia.specs = args
+		return nil
+	}
+}
+
+func installAction(ia *installCmdArgs) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		args := ia.parse(ctx)
+		if ia.dryRun {
+			previews, err := previewPlans(ctx, args)
+			if err != nil {
+				return err
+			}
+			if res := resultsFrom(ctx); res.format.structured() {
+				return res.print(previews)
+			}
+			return printPreviews(ctx, previews)
+		}
+		installed := make([]*download.Result, 0, len(args))
+		for _, a := range args {
+			result, err := installer.Install(ctx, a)
+			if err != nil {
+				return err
+			}
+			installed = append(installed, result)
+		}
+		if res := resultsFrom(ctx); res.format.structured() {
+			return res.print(installed)
+		}
+		return nil
+	}
+}
+
+func (ia *installCmdArgs) parse(ctx context.Context) []download.Args {
+	cfg := config.FromContext(ctx)
+	binDir := ia.binDir
+	if binDir == "" {
+		binDir = configdir.Bin(ctx)
 	}
+	args := make([]download.Args, 0, len(ia.specs))
+	for _, spec := range ia.specs {
+		a := install.Parse(spec)
+		a.Site = cfg.Site(ia.site)
+		a.Checksums = github.Checksums{FileName: ia.checksumsFilename()}
+		a.MultipleBinaries = ia.multipleBinaries
+		a.VerifyInArchive = ia.verifyInArchive
+		a.PreRelease = ia.preRelease || cfg.PreReleases()
+		args = append(args, download.Args{
+			Args:        a.WithDefaults(),
+			Destination: binDir,
+			Parallel:    ia.parallel,
+		})
+	}
+	return args
 }
 
 type installArgs struct {
@@ -35,6 +155,9 @@ type installArgs struct {
 	repo             string
 	multipleBinaries bool
 	verifyInArchive  bool
+	preRelease       bool
+	dryRun           bool
+	parallel         int
 }
 
 func (ia *installArgs) defaults() installArgs {
@@ -52,7 +175,7 @@ func (ia *installArgs) setFlags(c *cobra.Command) {
 	fl.StringVar(&ia.site, "site",
 		defs.site, "a site to download from")
 	fl.StringVarP(&ia.version, "version", "v",
-		defs.version, "a version to download")
+		defs.version, "a version, or a version constraint like ^1.2, to download")
 	fl.StringVar(&ia.basename, "basename",
 		defs.basename, "a basename of the artifact, "+
 			"if not given a repo name will be used")
@@ -62,6 +185,9 @@ func (ia *installArgs) setFlags(c *cobra.Command) {
 		"if set, will extract all binaries from the archive")
 	fl.BoolVar(&ia.verifyInArchive, "verify-in-archive", defs.verifyInArchive,
 		"if set, will verify the checksums against the binaries in the archive")
+	ia.setPreReleaseFlag(c)
+	ia.setParallelFlag(c)
+	ia.setDryRunFlag(c)
 	c.Args = cobra.ExactArgs(1)
 }
 
@@ -95,14 +221,15 @@ func (ia *installArgs) parse(ctx context.Context) install.Args {
 		Site:             cfg.Site(ia.site),
 		MultipleBinaries: ia.multipleBinaries,
 		VerifyInArchive:  ia.verifyInArchive,
+		PreRelease:       ia.preRelease || cfg.PreReleases(),
 	}
 	args = args.WithDefaults()
 	return args
 }
 
-func (ia *installArgs) repository() github.Repository {
+func (ia *installArgs) repository() artifact.Repository {
 	m := reporRe.FindStringSubmatch(ia.repo)
-	return github.Repository{
+	return artifact.Repository{
 		Owner: m[1],
 		Repo:  m[2],
 	}
@@ -1,13 +1,64 @@
 package ght
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
 
-func listCmd(_ *Args) *cobra.Command {
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/spf13/cobra"
+)
+
+const timeFormat = "2006-01-02 15:04"
+
+func listCmd(args *Args) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List the installed artifacts",
-		Run: func(cmd *cobra.Command, _ []string) {
-			cmd.Println("list")
-		},
+		Args:  cobra.NoArgs,
+		RunE:  handle(args, listAction),
+	}
+}
+
+func listAction(ctx context.Context) error {
+	db, err := state.New(ctx).Load(ctx)
+	if err != nil {
+		return err
+	}
+	insts := db.Installations
+	sort.Slice(insts, func(i, j int) bool {
+		return insts[i].Repository.String() < insts[j].Repository.String()
+	})
+	res := resultsFrom(ctx)
+	if res.format.structured() {
+		if insts == nil {
+			insts = []state.Installation{}
+		}
+		return res.print(insts)
+	}
+	tw := newTableWriter(res.out)
+	_, _ = fmt.Fprintln(tw, "REPOSITORY\tTAG\tASSET\tBINARIES\tINSTALLED")
+	for _, inst := range insts {
+		active := inst.Active()
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			inst.Repository, inst.Tag, assetNames(active.Assets),
+			strings.Join(inst.Links, ","),
+			active.InstalledAt.Local().Format(timeFormat))
+	}
+	return errors.WithStack(tw.Flush())
+}
+
+func assetNames(assets []artifact.Asset) string {
+	index := artifact.CreateIndex(assets)
+	names := make([]string, 0, len(index.Archives)+len(index.Binaries))
+	for _, asset := range index.Archives {
+		names = append(names, asset.Name)
+	}
+	for _, asset := range index.Binaries {
+		names = append(names, asset.Name)
 	}
+	return strings.Join(names, ",")
 }
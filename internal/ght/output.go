@@ -0,0 +1,114 @@
+package ght
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"emperror.dev/errors"
+	"knative.dev/client/pkg/output"
+	"sigs.k8s.io/yaml"
+)
+
+var errUnsupportedOutput = errors.New("unsupported output format")
+
+const tablePadding = 2
+
+type outputFormat string
+
+const (
+	outputText outputFormat = ""
+	outputJSON outputFormat = "json"
+	outputYAML outputFormat = "yaml"
+)
+
+func (f outputFormat) validate() error {
+	switch f {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", errUnsupportedOutput, f)
+}
+
+func (f outputFormat) structured() bool {
+	return f == outputJSON || f == outputYAML
+}
+
+func (f outputFormat) print(w io.Writer, v any) error {
+	var (
+		bytes []byte
+		err   error
+	)
+	switch f {
+	case outputJSON:
+		bytes, err = json.MarshalIndent(v, "", "  ")
+		bytes = append(bytes, '\n')
+	case outputYAML:
+		bytes, err = yaml.Marshal(v)
+	case outputText:
+		return fmt.Errorf("%w: text", errUnsupportedOutput)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = w.Write(bytes)
+	return errors.WithStack(err)
+}
+
+func newTableWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', 0)
+}
+
+type resultsKey struct{}
+
+// results is where the commands print their results to.
+type results struct {
+	format outputFormat
+	out    io.Writer
+}
+
+// withResults puts the results into the context. With a structured output,
+// the feedback meant for humans is moved to the standard error, so the
+// standard output carries only the results.
+func withResults(ctx context.Context, format outputFormat) context.Context {
+	prt := output.PrinterFrom(ctx)
+	res := results{format: format, out: prt.OutOrStdout()}
+	if format.structured() {
+		ctx = output.WithContext(ctx, feedbackPrinter{Printer: prt})
+	}
+	return context.WithValue(ctx, resultsKey{}, res)
+}
+
+func resultsFrom(ctx context.Context) results {
+	if res, ok := ctx.Value(resultsKey{}).(results); ok {
+		return res
+	}
+	return results{out: output.PrinterFrom(ctx).OutOrStdout()}
+}
+
+func (r results) print(v any) error {
+	return r.format.print(r.out, v)
+}
+
+// feedbackPrinter prints everything to the standard error.
+type feedbackPrinter struct {
+	output.Printer
+}
+
+func (p feedbackPrinter) Print(i ...any) {
+	p.PrintErr(i...)
+}
+
+func (p feedbackPrinter) Println(i ...any) {
+	p.PrintErrln(i...)
+}
+
+func (p feedbackPrinter) Printf(format string, i ...any) {
+	p.PrintErrf(format, i...)
+}
+
+func (p feedbackPrinter) OutOrStdout() io.Writer {
+	return p.ErrOrStderr()
+}
@@ -0,0 +1,54 @@
+package ght
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/download"
+)
+
+// previewPlans creates the plans, and describes them, without downloading
+// anything.
+func previewPlans(ctx context.Context, args []download.Args) ([]download.Preview, error) {
+	previews := make([]download.Preview, 0, len(args))
+	for _, a := range args {
+		plan, err := download.CreatePlan(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, plan.Preview(a))
+	}
+	return previews, nil
+}
+
+func printPreviews(ctx context.Context, previews []download.Preview) error {
+	out := resultsFrom(ctx).out
+	for i, pv := range previews {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "%s %s\n", pv.Repository, pv.Tag)
+		tw := newTableWriter(out)
+		_, _ = fmt.Fprintln(tw, "ASSET\tCATEGORY\tSIZE")
+		for _, a := range pv.Assets {
+			size := "-"
+			if a.Size > 0 {
+				size = strconv.Itoa(a.Size)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Name, a.Category, size)
+		}
+		if err := tw.Flush(); err != nil {
+			return errors.WithStack(err)
+		}
+		checksum := pv.Checksum
+		if checksum == "" {
+			checksum = "-"
+		}
+		_, _ = fmt.Fprintf(out, "Checksum: %s\nTargets: %s\n",
+			checksum, strings.Join(pv.Targets, ", "))
+	}
+	return nil
+}
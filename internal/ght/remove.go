@@ -1,13 +1,32 @@
 package ght
 
-import "github.com/spf13/cobra"
+import (
+	"context"
 
-func removeCmd(_ *Args) *cobra.Command {
-	return &cobra.Command{
-		Use:   "remove",
+	"github.com/cardil/ghet/pkg/ghet/remove"
+	"github.com/spf13/cobra"
+)
+
+func removeCmd(args *Args) *cobra.Command {
+	ra := &remove.Args{}
+	c := &cobra.Command{
+		Use:   "remove [flags] <owner>/<repo>|<binary>...",
 		Short: "Remove an installed artifact",
-		Run: func(cmd *cobra.Command, _ []string) {
-			cmd.Println("remove")
+		Args:  cobra.MinimumNArgs(1),
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			ra.Tools = args
+			return nil
 		},
+		RunE: handle(args, func(ctx context.Context) error {
+			return remove.Action(ctx, *ra)
+		}),
+		Example: "\n * ght remove cardil/ghet" +
+			"\n * ght remove --dry-run k9s",
 	}
+	fl := c.Flags()
+	fl.BoolVar(&ra.Force, "force", false,
+		"if set, will remove binaries even if they were modified after installation")
+	fl.BoolVar(&ra.DryRun, "dry-run", false,
+		"if set, will only list the files that would be removed")
+	return c
 }
@@ -0,0 +1,55 @@
+package ght
+
+import (
+	"context"
+
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/shim"
+	"github.com/spf13/cobra"
+)
+
+type shimArgs struct {
+	tools  []string
+	remove bool
+}
+
+func shimCmd(args *Args) *cobra.Command {
+	sa := &shimArgs{}
+	c := &cobra.Command{
+		Use:   "shim [flags] [<owner>/<repo>|<binary>...]",
+		Short: "Place shims, picking the version pinned by the project, into the bin dir",
+		Long: "Place shims into the bin dir, instead of the links to the active " +
+			"versions. A shim runs the version pinned by the nearest .ghet.lock " +
+			"or .ghet.yaml, falling back to the active version.",
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			sa.tools = args
+			return nil
+		},
+		RunE: handle(args, func(ctx context.Context) error {
+			return installer.Shim(ctx, sa.tools, !sa.remove)
+		}),
+		Example: "\n * ght shim" +
+			"\n * ght shim --remove kubectl",
+	}
+	c.Flags().BoolVar(&sa.remove, "remove", false,
+		"if set, will replace the shims back with the links to the active versions")
+	return c
+}
+
+func execCmd(args *Args) *cobra.Command {
+	var argv []string
+	return &cobra.Command{
+		Use:                "exec <binary> [args...]",
+		Short:              "Run the binary of the version pinned by the project",
+		Hidden:             true,
+		DisableFlagParsing: true,
+		Args:               cobra.MinimumNArgs(1),
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			argv = args
+			return nil
+		},
+		RunE: handle(args, func(ctx context.Context) error {
+			return shim.Exec(ctx, argv[0], argv[1:])
+		}),
+	}
+}
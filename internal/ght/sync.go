@@ -0,0 +1,41 @@
+package ght
+
+import (
+	"context"
+
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/manifest"
+	"github.com/spf13/cobra"
+)
+
+func syncCmd(args *Args) *cobra.Command {
+	sa := &manifest.Args{}
+	c := &cobra.Command{
+		Use:   "sync [flags]",
+		Short: "Install the tools listed in the project's " + manifest.FileName,
+		Long: "Install the tools listed in the project's " + manifest.FileName +
+			". The tools pinned in " + manifest.LockFileName + " are installed " +
+			"exactly as locked, the others are resolved and pinned.",
+		Args: cobra.NoArgs,
+		RunE: handle(args, func(ctx context.Context) error {
+			if sa.BinDir == "" {
+				sa.BinDir = configdir.Bin(ctx)
+			}
+			return manifest.Action(ctx, *sa)
+		}),
+		Example: "\n * ght sync" +
+			"\n * ght sync --frozen -C path/to/project",
+	}
+	fl := c.Flags()
+	fl.StringVarP(&sa.Dir, "dir", "C", ".",
+		"a project directory, holding the "+manifest.FileName+" manifest")
+	fl.StringVarP(&sa.BinDir, "bin-dir", "b", "",
+		"a directory to install binaries to (default $"+
+			configdir.BinDirEnvName+" or ~/.local/bin)")
+	fl.IntVar(&sa.Parallel, "parallel", download.DefaultParallel,
+		"a number of assets to download at once")
+	fl.BoolVar(&sa.Frozen, "frozen", false,
+		"if set, will fail instead of updating the "+manifest.LockFileName)
+	return c
+}
@@ -0,0 +1,101 @@
+package ght
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/upgrade"
+	"github.com/spf13/cobra"
+	"knative.dev/client/pkg/output/tui"
+)
+
+func upgradeCmd(args *Args) *cobra.Command {
+	ua := &upgrade.Args{}
+	c := &cobra.Command{
+		Use:   "upgrade [flags] [<owner>/<repo>|<binary>...]",
+		Short: "Upgrade the installed artifacts to their latest releases",
+		Long: "Upgrade the installed artifacts to their latest releases. " +
+			"Exits with error if there were any upgrades, applied or not.",
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			ua.Tools = args
+			return nil
+		},
+		RunE: handle(args, upgradeAction(ua)),
+		Example: "\n * ght upgrade" +
+			"\n * ght upgrade --dry-run derailed/k9s",
+	}
+	c.Flags().BoolVar(&ua.DryRun, "dry-run", false,
+		"if set, will only list the upgrades")
+	c.Flags().IntVar(&ua.Parallel, "parallel", download.DefaultParallel,
+		"a number of assets to download at once")
+	return c
+}
+
+func upgradeAction(ua *upgrade.Args) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		updates, err := upgrade.Action(ctx, *ua)
+		if err != nil && !errors.Is(err, upgrade.ErrUpdatesAvailable) {
+			return err
+		}
+		if res := resultsFrom(ctx); res.format.structured() {
+			if perr := res.print(updates); perr != nil {
+				return perr
+			}
+		}
+		return err
+	}
+}
+
+type outdatedArgs struct {
+	tools []string
+}
+
+func outdatedCmd(args *Args) *cobra.Command {
+	oa := &outdatedArgs{}
+	return &cobra.Command{
+		Use:   "outdated [flags] [<owner>/<repo>|<binary>...]",
+		Short: "List the installed artifacts with newer releases available",
+		Long: "List the installed artifacts with newer releases available. " +
+			"Exits with error if there are any.",
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			oa.tools = args
+			return nil
+		},
+		RunE: handle(args, outdatedAction(oa)),
+	}
+}
+
+func outdatedAction(oa *outdatedArgs) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		updates, err := upgrade.Outdated(ctx, oa.tools)
+		if err != nil {
+			return err
+		}
+		res := resultsFrom(ctx)
+		switch {
+		case res.format.structured():
+			if err = res.print(updates); err != nil {
+				return err
+			}
+		case len(updates) == 0:
+			tui.NewWidgets(ctx).Printf("🎉 All tools are up to date")
+		default:
+			tw := newTableWriter(res.out)
+			_, _ = fmt.Fprintln(tw, "REPOSITORY\tINSTALLED\tLATEST")
+			for _, u := range updates {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n",
+					u.Repository, u.Installed, u.Latest)
+			}
+			if err = tw.Flush(); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if len(updates) > 0 {
+			return errors.WithStack(fmt.Errorf("%w: %d",
+				upgrade.ErrUpdatesAvailable, len(updates)))
+		}
+		return nil
+	}
+}
@@ -5,12 +5,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func versionCmd(_ *Args) *cobra.Command {
+func versionCmd(args *Args) *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Print the version information",
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format := outputFormat(args.Output); format.structured() {
+				return format.print(cmd.OutOrStdout(), map[string]string{
+					"version": metadata.Version,
+				})
+			}
 			cmd.Println(metadata.Version)
+			return nil
 		},
 	}
 }
@@ -0,0 +1,115 @@
+package ght
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/spf13/cobra"
+)
+
+var errVersionNotGiven = errors.New("version not given")
+
+func useCmd(args *Args) *cobra.Command {
+	var tool, tag string
+	return &cobra.Command{
+		Use:   "use <owner>/<repo>|<binary>@<tag>",
+		Short: "Switch to another installed version of the artifact",
+		Args:  cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var ok bool
+			if tool, tag, ok = strings.Cut(args[0], "@"); !ok || tag == "" {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("%w: %q", errVersionNotGiven, args[0])
+			}
+			return nil
+		},
+		RunE: handle(args, func(ctx context.Context) error {
+			return installer.Use(ctx, tool, tag)
+		}),
+		Example: "\n * ght use kubernetes/kubectl@v1.28.4" +
+			"\n * ght use kubectl@v1.30.0",
+	}
+}
+
+type versionsArgs struct {
+	tool string
+}
+
+func versionsCmd(args *Args) *cobra.Command {
+	va := &versionsArgs{}
+	return &cobra.Command{
+		Use:   "versions [flags] <owner>/<repo>|<binary>",
+		Short: "List the installed versions of the artifact",
+		Args:  cobra.ExactArgs(1),
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			va.tool = args[0]
+			return nil
+		},
+		RunE: handle(args, versionsAction(va)),
+	}
+}
+
+type installedVersion struct {
+	state.InstalledVersion
+	Active bool `json:"active"`
+}
+
+func versionsAction(va *versionsArgs) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		db, err := state.New(ctx).Load(ctx)
+		if err != nil {
+			return err
+		}
+		insts, err := db.Resolve([]string{va.tool})
+		if err != nil {
+			return err
+		}
+		inst := insts[0]
+		versions := make([]installedVersion, 0, len(inst.Versions))
+		for _, v := range inst.Versions {
+			versions = append(versions, installedVersion{
+				InstalledVersion: v,
+				Active:           v.Tag == inst.Tag,
+			})
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return newerTag(versions[i].Tag, versions[j].Tag)
+		})
+		res := resultsFrom(ctx)
+		if res.format.structured() {
+			return res.print(versions)
+		}
+		tw := newTableWriter(res.out)
+		_, _ = fmt.Fprintln(tw, "TAG\tACTIVE\tASSET\tINSTALLED")
+		for _, v := range versions {
+			active := ""
+			if v.Active {
+				active = "*"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+				v.Tag, active, assetNames(v.Assets),
+				v.InstalledAt.Local().Format(timeFormat))
+		}
+		return errors.WithStack(tw.Flush())
+	}
+}
+
+// newerTag orders the tags by their semantic versions, newest first. Tags that
+// aren't semantic versions are ordered by name, after the others.
+func newerTag(a, b string) bool {
+	va, aerr := github.ParseTagVersion(a)
+	vb, berr := github.ParseTagVersion(b)
+	switch {
+	case aerr == nil && berr == nil:
+		return va.GreaterThan(vb)
+	case aerr == nil || berr == nil:
+		return aerr == nil
+	}
+	return a > b
+}
@@ -1,15 +1,17 @@
-package api
+package artifact
 
 import (
+	"slices"
+
 	"github.com/cardil/ghet/pkg/match"
 )
 
 type Asset struct {
-	ID          int64
-	Name        string
-	ContentType string
-	Size        int
-	URL         string
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+	URL         string `json:"url"`
 }
 
 func (a Asset) String() string {
@@ -22,11 +24,15 @@ type IndexedAssets struct {
 	Binaries  []Asset
 }
 
-func CreateIndex(assets []Asset) IndexedAssets {
+// CreateIndex sorts the assets by their kinds. The names of the checksum
+// files, which wouldn't be recognized otherwise, can be given.
+func CreateIndex(assets []Asset, checksums ...string) IndexedAssets {
 	index := IndexedAssets{}
 	for _, asset := range assets {
 		name := asset.Name
 		switch {
+		case slices.Contains(checksums, name):
+			index.Checksums = append(index.Checksums, asset)
 		case isArchive().Matches(name):
 			index.Archives = append(index.Archives, asset)
 		case isChecksum().Matches(name):
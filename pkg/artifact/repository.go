@@ -0,0 +1,13 @@
+// Package artifact describes the repositories and their release assets,
+// regardless of the site hosting them.
+package artifact
+
+// Repository is a repository hosted on a site, like github.com.
+type Repository struct {
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+}
+
+func (r Repository) String() string {
+	return r.Owner + "/" + r.Repo
+}
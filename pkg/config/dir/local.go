@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"path"
 
 	"emperror.dev/errors"
 	"github.com/cardil/ghet/pkg/metadata"
@@ -11,14 +12,20 @@ import (
 )
 
 const (
-	ConfigDirEnvName = "GHET_CONFIG_DIR"
-	CacheDirEnvName  = "GHET_CACHE_DIR"
+	ConfigDirEnvName   = "GHET_CONFIG_DIR"
+	CacheDirEnvName    = "GHET_CACHE_DIR"
+	BinDirEnvName      = "GHET_BIN_DIR"
+	VersionsDirEnvName = "GHET_VERSIONS_DIR"
 )
 
 type cacheDirKey struct{}
 
 type configDirKey struct{}
 
+type binDirKey struct{}
+
+type versionsDirKey struct{}
+
 func Config(ctx context.Context) string {
 	return userPath(ctx, configDirKey{}, ConfigDirEnvName, func() string {
 		return configdir.LocalConfig(metadata.Name)
@@ -31,6 +38,30 @@ func Cache(ctx context.Context) string {
 	})
 }
 
+// Bin returns a directory the installed binaries are placed into. By default,
+// it's the ~/.local/bin directory.
+func Bin(ctx context.Context) string {
+	return userPath(ctx, binDirKey{}, BinDirEnvName, func() string {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			log.Fatal(errors.WithStack(err))
+		}
+		return path.Join(home, ".local", "bin")
+	})
+}
+
+// Versions returns a directory the installed versions of the tools are kept
+// in. By default, it's the ~/.local/share/ght/versions directory.
+func Versions(ctx context.Context) string {
+	return userPath(ctx, versionsDirKey{}, VersionsDirEnvName, func() string {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			log.Fatal(errors.WithStack(err))
+		}
+		return path.Join(home, ".local", "share", metadata.Name, "versions")
+	})
+}
+
 func userPath(ctx context.Context, key interface{}, envKey string, fn func() string) string {
 	if p, ok := ctx.Value(key).(string); ok {
 		return ensurePathExists(p)
@@ -9,3 +9,11 @@ func WithConfigDir(ctx context.Context, p string) context.Context {
 func WithCacheDir(ctx context.Context, p string) context.Context {
 	return context.WithValue(ctx, cacheDirKey{}, p)
 }
+
+func WithBinDir(ctx context.Context, p string) context.Context {
+	return context.WithValue(ctx, binDirKey{}, p)
+}
+
+func WithVersionsDir(ctx context.Context, p string) context.Context {
+	return context.WithValue(ctx, versionsDirKey{}, p)
+}
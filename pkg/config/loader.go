@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"text/template"
 
 	"emperror.dev/errors"
 	"knative.dev/client/pkg/output/logging"
@@ -21,7 +22,14 @@ func Load(ctx context.Context, file string) (Config, error) {
 		Sites: []Site{{
 			Type:    TypeGitHub,
 			Address: "github.com",
+		}, {
+			Type:    TypeGitLab,
+			Address: "gitlab.com",
+		}, {
+			Type:    TypeGitea,
+			Address: "codeberg.org",
 		}},
+		Channel: ChannelStable,
 	}
 	var cfg Config
 	if fileNotExists(file) {
@@ -36,8 +44,54 @@ func Load(ctx context.Context, file string) (Config, error) {
 	if err != nil {
 		return Config{}, asInvalidConfigErr(err)
 	}
+	if err = cfg.validate(); err != nil {
+		return Config{}, asInvalidConfigErr(err)
+	}
+
+	return defaults.Merge(cfg).compileRules()
+}
+
+func (c Config) validate() error {
+	switch c.Channel {
+	case "", ChannelStable, ChannelPreRelease:
+	default:
+		return fmt.Errorf("%w: unknown channel: %q", ErrInvalidConfigFile, c.Channel)
+	}
+	if c.Retry.Attempts != nil && *c.Retry.Attempts < 0 {
+		return fmt.Errorf("%w: negative retry attempts: %d",
+			ErrInvalidConfigFile, *c.Retry.Attempts)
+	}
+	if c.Retry.MaxWait.Duration < 0 {
+		return fmt.Errorf("%w: negative retry max wait: %s",
+			ErrInvalidConfigFile, c.Retry.MaxWait)
+	}
+	for _, site := range c.Sites {
+		if err := site.validate(); err != nil {
+			return err
+		}
+	}
+	for _, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return defaults.Merge(cfg), nil
+func (s Site) validate() error {
+	if s.Type != TypeURL {
+		return nil
+	}
+	if s.Template == nil || s.Template.URL == "" {
+		return fmt.Errorf("%w: site %q: missing URL template",
+			ErrInvalidConfigFile, s.Address)
+	}
+	for _, tpl := range []string{s.Template.URL, s.Template.ChecksumURL} {
+		if _, err := template.New(s.Address).Parse(tpl); err != nil {
+			return fmt.Errorf("%w: site %q: %v", ErrInvalidConfigFile, s.Address, err)
+		}
+	}
+	return nil
 }
 
 func fileNotExists(file string) bool {
@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestLoadRetry(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	fp := path.Join(t.TempDir(), "settings.yaml")
+	require.NoError(t, os.WriteFile(fp,
+		[]byte("retry:\n  attempts: 5\n  maxWait: 2m30s\n"), 0o600))
+
+	cfg, err := config.Load(ctx, fp)
+	require.NoError(t, err)
+	policy := cfg.Retry.Policy()
+	assert.Equal(t, 5, policy.Attempts)
+	assert.Equal(t, 150*time.Second, policy.MaxWait)
+
+	require.NoError(t, os.WriteFile(fp,
+		[]byte("retry:\n  attempts: -1\n"), 0o600))
+	_, err = config.Load(ctx, fp)
+	require.ErrorIs(t, err, config.ErrInvalidConfigFile)
+}
+
+func TestLoadCustomSite(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	fp := path.Join(t.TempDir(), "settings.yaml")
+	require.NoError(t, os.WriteFile(fp, []byte(`sites:
+  - type: gitea
+    address: git.example.com
+    auth:
+      token: secret
+`), 0o600))
+
+	cfg, err := config.Load(ctx, fp)
+	require.NoError(t, err)
+	assert.Equal(t, config.Site{
+		Type:    config.TypeGitea,
+		Address: "git.example.com",
+		Auth:    &config.Auth{Token: "secret"},
+	}, cfg.Site("git.example.com"))
+	assert.Equal(t, config.TypeGitea, cfg.Site("codeberg.org").Type)
+}
+
+func TestLoadURLSite(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	fp := path.Join(t.TempDir(), "settings.yaml")
+	require.NoError(t, os.WriteFile(fp, []byte(`sites:
+  - type: url
+    address: dl.k8s.io
+    template:
+      url: https://dl.k8s.io/release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/kubectl{{.Ext}}
+      checksumUrl: https://dl.k8s.io/release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/kubectl{{.Ext}}.sha256
+`), 0o600))
+
+	cfg, err := config.Load(ctx, fp)
+	require.NoError(t, err)
+	site := cfg.Site("dl.k8s.io")
+	assert.Equal(t, config.TypeURL, site.Type)
+	require.NotNil(t, site.Template)
+	assert.Contains(t, site.Template.ChecksumURL, ".sha256")
+
+	for _, invalid := range []string{
+		"sites:\n  - type: url\n    address: dl.k8s.io\n",
+		"sites:\n  - type: url\n    address: dl.k8s.io\n    template:\n      url: https://dl.k8s.io/{{.Tag\n",
+	} {
+		require.NoError(t, os.WriteFile(fp, []byte(invalid), 0o600))
+		_, err = config.Load(ctx, fp)
+		require.ErrorIs(t, err, config.ErrInvalidConfigFile)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	fp := path.Join(t.TempDir(), "settings.yaml")
+	require.NoError(t, os.WriteFile(fp, []byte(`rules:
+  - repository: example/tool
+    asset: "tool_*.tgz"
+    aliases:
+      aarch64: arm64
+      macos: darwin
+    checksums: SHASUMS256.txt
+    binary: bin/tool
+`), 0o600))
+
+	cfg, err := config.Load(ctx, fp)
+	require.NoError(t, err)
+	rule, ok := cfg.Rule(artifact.Repository{Owner: "example", Repo: "tool"})
+	require.True(t, ok)
+	assert.Equal(t, "SHASUMS256.txt", rule.Checksums)
+	m := rule.Matchers()
+	require.NotNil(t, m)
+	assert.True(t, m.Asset.Matches("tool_macos_aarch64.tgz"))
+	assert.False(t, m.Asset.Matches("tool_macos_aarch64.zip"))
+	assert.True(t, m.Architectures[github.ArchARM64].Matches("tool_macos_aarch64.tgz"))
+	assert.True(t, m.OperatingSystems[github.OSDarwin].Matches("tool_macos_aarch64.tgz"))
+	assert.Equal(t, "bin/tool", m.Binary)
+	_, ok = cfg.Rule(artifact.Repository{Owner: "example", Repo: "other"})
+	assert.False(t, ok)
+
+	for _, invalid := range []string{
+		"rules:\n  - repository: tool\n",
+		"rules:\n  - repository: example/tool\n    asset: '[a-'\n",
+		"rules:\n  - repository: example/tool\n    assetRegex: '(tool'\n",
+		"rules:\n  - repository: example/tool\n    asset: a\n    assetRegex: a\n",
+		"rules:\n  - repository: example/tool\n    aliases:\n      m1: apple-silicon\n",
+	} {
+		require.NoError(t, os.WriteFile(fp, []byte(invalid), 0o600))
+		_, err = config.Load(ctx, fp)
+		assert.ErrorIs(t, err, config.ErrInvalidConfigFile, invalid)
+	}
+}
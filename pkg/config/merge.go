@@ -1,19 +1,36 @@
 package config
 
 func (c Config) Merge(cfg Config) Config {
-	if len(cfg.Sites) == 0 {
-		return c
+	if cfg.Channel != "" {
+		c.Channel = cfg.Channel
 	}
-	if len(c.Sites) == 0 {
-		return cfg
+	if cfg.Retry.Attempts != nil {
+		c.Retry.Attempts = cfg.Retry.Attempts
 	}
-	matched := make([]pair, 0, len(cfg.Sites))
-	unmatched := make([]Site, 0, len(cfg.Sites))
-	for _, site := range c.Sites {
+	if cfg.Retry.MaxWait.Duration > 0 {
+		c.Retry.MaxWait = cfg.Retry.MaxWait
+	}
+	c.Sites = mergeSites(c.Sites, cfg.Sites)
+	c.Rules = append(c.Rules, cfg.Rules...)
+	return c
+}
+
+func mergeSites(defaults, overrides []Site) []Site {
+	if len(overrides) == 0 {
+		return defaults
+	}
+	if len(defaults) == 0 {
+		return overrides
+	}
+	matched := make([]pair, 0, len(overrides))
+	unmatched := make([]Site, 0, len(overrides))
+	used := make([]bool, len(overrides))
+	for _, site := range defaults {
 		found := false
-		for _, cfgSite := range cfg.Sites {
+		for i, cfgSite := range overrides {
 			if cfgSite.Match(site) {
 				matched = append(matched, pair{site, cfgSite})
+				used[i] = true
 				found = true
 				break
 			}
@@ -27,7 +44,17 @@ func (c Config) Merge(cfg Config) Config {
 		sites = append(sites, p.original.Merge(p.replacement))
 	}
 	sites = append(sites, unmatched...)
-	return Config{Sites: sites}
+	// The sites not known by defaults, like GitHub Enterprise Servers, are
+	// added as configured.
+	for i, cfgSite := range overrides {
+		if !used[i] && cfgSite.Address != "" {
+			if cfgSite.Type == "" {
+				cfgSite.Type = TypeGitHub
+			}
+			sites = append(sites, cfgSite)
+		}
+	}
+	return sites
 }
 
 func (s Site) Match(site Site) bool {
@@ -47,6 +74,10 @@ func (s Site) Merge(override Site) Site {
 	if s.Auth == nil {
 		s.Auth = override.copy()
 	}
+	if s.Template == nil && override.Template != nil {
+		tpl := *override.Template
+		s.Template = &tpl
+	}
 	return s
 }
 
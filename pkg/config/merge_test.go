@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/retry"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +39,54 @@ func TestMerge(t *testing.T) {
 		},
 	}, merged.Sites[0])
 }
+
+func TestMergeChannel(t *testing.T) {
+	defaults := config.Config{
+		Sites:   []config.Site{{Type: config.TypeGitHub, Address: "github.com"}},
+		Channel: config.ChannelStable,
+	}
+
+	merged := defaults.Merge(config.Config{Channel: config.ChannelPreRelease})
+
+	assert.Equal(t, config.ChannelPreRelease, merged.Channel)
+	assert.True(t, merged.PreReleases())
+	assert.Equal(t, defaults.Sites, merged.Sites)
+	assert.False(t, defaults.Merge(config.Config{}).PreReleases())
+}
+
+func TestMergeRetry(t *testing.T) {
+	attempts := 0
+	defaults := config.Config{}
+
+	assert.Equal(t, retry.DefaultPolicy(), defaults.Retry.Policy())
+
+	merged := defaults.Merge(config.Config{Retry: config.Retry{
+		Attempts: &attempts,
+		MaxWait:  config.Duration{Duration: 5 * time.Minute},
+	}})
+
+	assert.Equal(t, retry.Policy{
+		Attempts: 0,
+		MaxWait:  5 * time.Minute,
+	}, merged.Retry.Policy())
+}
+
+func TestMergeEnterpriseSite(t *testing.T) {
+	defaults := config.Config{
+		Sites: []config.Site{{Type: config.TypeGitHub, Address: "github.com"}},
+	}
+	ghe := config.Site{
+		Address: "ghe.example.com",
+		Auth:    &config.Auth{Token: "token"},
+	}
+
+	merged := defaults.Merge(config.Config{Sites: []config.Site{ghe}})
+
+	assert.Len(t, merged.Sites, 2)
+	ghe.Type = config.TypeGitHub
+	assert.Equal(t, ghe, merged.Site("ghe.example.com"))
+	assert.Equal(t, config.Site{
+		Type:    config.TypeGitHub,
+		Address: "ghe.other.com",
+	}, merged.Site("ghe.other.com"))
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/match"
+)
+
+// Rule customizes how the assets of a repository are matched, so the odd
+// upstream naming schemes can be handled.
+type Rule struct {
+	// Repository is the "owner/repo" the rule applies to.
+	Repository string `json:"repository"`
+	// Asset is a glob, like "tool_*.tgz", which selects the asset names in
+	// place of the base name.
+	Asset string `json:"asset,omitempty"`
+	// AssetRegex is like Asset, but a regular expression.
+	AssetRegex string `json:"assetRegex,omitempty"`
+	// Aliases are the extra names of the architectures and the operating
+	// systems, like "aarch64: arm64" or "macos: darwin".
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Checksums is the name of the checksum file.
+	Checksums string `json:"checksums,omitempty"`
+	// Binary is the name, or the path, of the binary inside the archive.
+	Binary string `json:"binary,omitempty"`
+
+	matchers *github.Rules
+}
+
+// Rule returns the rule configured for the repository.
+func (c Config) Rule(repo artifact.Repository) (Rule, bool) {
+	for _, r := range c.Rules {
+		if r.Repository == repo.String() {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Matchers returns the matchers built from the rule, when the config was
+// loaded.
+func (r Rule) Matchers() *github.Rules {
+	if r.matchers != nil {
+		return r.matchers
+	}
+	m, err := r.compile()
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+func (r Rule) validate() error {
+	owner, repo, ok := strings.Cut(r.Repository, "/")
+	if !ok || owner == "" || repo == "" {
+		return fmt.Errorf("%w: rule: invalid repository: %q",
+			ErrInvalidConfigFile, r.Repository)
+	}
+	if r.Asset != "" && r.AssetRegex != "" {
+		return fmt.Errorf("%w: rule %s: both asset and assetRegex given",
+			ErrInvalidConfigFile, r.Repository)
+	}
+	return nil
+}
+
+func (r Rule) compile() (*github.Rules, error) {
+	var asset match.Matcher
+	switch {
+	case r.Asset != "":
+		if _, err := path.Match(r.Asset, ""); err != nil {
+			return nil, fmt.Errorf("asset %q: %w", r.Asset, err)
+		}
+		asset = match.Glob(r.Asset)
+	case r.AssetRegex != "":
+		rx, err := regexp.Compile(r.AssetRegex)
+		if err != nil {
+			return nil, fmt.Errorf("assetRegex: %w", err)
+		}
+		asset = match.MatcherFn(rx.MatchString)
+	}
+	return github.NewRules(asset, r.Aliases, r.Binary) //nolint:wrapcheck
+}
+
+// compileRules builds the matchers of the rules, failing on the malformed
+// patterns and aliases.
+func (c Config) compileRules() (Config, error) {
+	rules := make([]Rule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		m, err := r.compile()
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: rule %s: %v",
+				ErrInvalidConfigFile, r.Repository, err)
+		}
+		r.matchers = m
+		rules = append(rules, r)
+	}
+	c.Rules = rules
+	return c, nil
+}
@@ -1,28 +1,117 @@
 package config
 
+import (
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/retry"
+)
+
 type Config struct {
-	Sites []Site `json:"sites"`
+	Sites   []Site  `json:"sites"`
+	Channel Channel `json:"channel,omitempty"`
+	Retry   Retry   `json:"retry,omitempty"`
+	// Rules customize the matching of the assets of the repositories.
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Retry controls how the failed requests are retried.
+type Retry struct {
+	// Attempts is the number of retries of a failed request.
+	Attempts *int `json:"attempts,omitempty"`
+	// MaxWait is the longest time to wait before a retry, like "1m".
+	MaxWait Duration `json:"maxWait,omitempty"`
+}
+
+// Policy returns the retry policy, using the defaults for what isn't set.
+func (r Retry) Policy() retry.Policy {
+	p := retry.DefaultPolicy()
+	if r.Attempts != nil {
+		p.Attempts = *r.Attempts
+	}
+	if r.MaxWait.Duration > 0 {
+		p.MaxWait = r.MaxWait.Duration
+	}
+	return p
+}
+
+// Duration is a time.Duration, kept in a human-readable form like "1m30s".
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String()) //nolint:wrapcheck
 }
 
+func (d *Duration) UnmarshalJSON(bytes []byte) error {
+	var s string
+	if err := json.Unmarshal(bytes, &s); err != nil {
+		return errors.WithStack(err)
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	d.Duration = dur
+	return nil
+}
+
+// Channel controls which releases are considered when resolving versions.
+type Channel string
+
+const (
+	// ChannelStable considers only the regular releases.
+	ChannelStable Channel = "stable"
+	// ChannelPreRelease considers the pre-releases as well.
+	ChannelPreRelease Channel = "prerelease"
+)
+
+// PreReleases returns true if pre-releases should be considered.
+func (c Config) PreReleases() bool {
+	return c.Channel == ChannelPreRelease
+}
+
+// Site returns the configured site of the given address. A site that isn't
+// configured is assumed to be a GitHub Enterprise Server.
 func (c Config) Site(site string) Site {
 	for _, s := range c.Sites {
 		if s.Address == site {
 			return s
 		}
 	}
-	return Site{}
+	return Site{Type: TypeGitHub, Address: site}
 }
 
 type Type string
 
 const (
 	TypeGitHub Type = "github"
+	// TypeGitLab is a GitLab instance, like gitlab.com or a self-hosted one.
+	TypeGitLab Type = "gitlab"
+	// TypeGitea is a Gitea or Forgejo instance, like codeberg.org.
+	TypeGitea Type = "gitea"
+	// TypeURL is a site, like a CDN, that serves the assets at the URLs
+	// rendered from a template. The releases are discovered from the tags of
+	// the repository on github.com, so no GitHub releases are needed.
+	TypeURL Type = "url"
 )
 
 type Site struct {
-	Type    `json:"type"`
-	Address string `json:"address"`
-	*Auth   `json:"auth"`
+	Type      `json:"type"`
+	Address   string `json:"address"`
+	*Auth     `json:"auth"`
+	*Template `json:"template,omitempty"`
+}
+
+// Template renders the URLs of the assets of the sites of TypeURL. The
+// templates are Go text templates, given the release and platform, like
+// "https://dl.k8s.io/release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/kubectl{{.Ext}}".
+type Template struct {
+	URL string `json:"url"`
+	// ChecksumURL is optional, and points to a checksum of the asset.
+	ChecksumURL string `json:"checksumUrl,omitempty"`
 }
 
 type Auth struct {
@@ -6,14 +6,14 @@ import (
 	"knative.dev/client/pkg/output/logging"
 )
 
-func Action(ctx context.Context, args Args) error {
+func Action(ctx context.Context, args Args) (*Result, error) {
 	ctx = logging.EnsureLogger(ctx, logging.Fields{
 		"owner": args.Owner,
 		"repo":  args.Repo,
 	})
 	plan, err := CreatePlan(ctx, args)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	return plan.Download(ctx, args)
 }
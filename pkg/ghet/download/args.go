@@ -1,10 +1,58 @@
 package download
 
 import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/cardil/ghet/pkg/config"
 	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
 )
 
 type Args struct {
 	install.Args
 	Destination string
+	// Digests are the expected SHA-256 digests of the downloaded assets, keyed
+	// by the asset name. If set, every downloaded asset must match one of them.
+	Digests map[string]string
+	// Parallel limits the number of assets downloaded at once. The
+	// DefaultParallel is used, if not set.
+	Parallel int
+}
+
+// DefaultParallel is the default number of assets downloaded at once.
+const DefaultParallel = 4
+
+func (a Args) parallel() int {
+	if a.Parallel > 0 {
+		return a.Parallel
+	}
+	return DefaultParallel
+}
+
+// withRules applies the matching rule configured for the repository. The
+// checksum file name of the rule is used, unless one is given explicitly.
+func (a Args) withRules(ctx context.Context) Args {
+	rule, ok := config.FromContext(ctx).Rule(a.Repository)
+	if !ok {
+		return a
+	}
+	a.Rules = rule.Matchers()
+	if rule.Checksums != "" && a.Checksums.ToString() == "" {
+		a.Checksums = pkggithub.Checksums{
+			FileName: pkggithub.NewFileName(rule.Checksums),
+		}
+	}
+	return a
+}
+
+// isBinary checks if the file, at the path inside the archive, is the
+// binary. The binary of the rule is matched by its name or path, otherwise
+// the name must contain the base name.
+func (a Args) isBinary(fp string) bool {
+	if a.Rules != nil && a.Rules.Binary != "" {
+		return fp == a.Rules.Binary || path.Base(fp) == a.Rules.Binary
+	}
+	return strings.Contains(path.Base(fp), a.BaseName)
 }
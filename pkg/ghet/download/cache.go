@@ -9,11 +9,11 @@ import (
 	"path"
 	"strconv"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	configdir "github.com/cardil/ghet/pkg/config/dir"
-	githubapi "github.com/cardil/ghet/pkg/github/api"
 )
 
-func (p Plan) cachePath(ctx context.Context, asset githubapi.Asset) string {
+func (p Plan) cachePath(ctx context.Context, asset artifact.Asset) string {
 	dir := path.Join(configdir.Cache(ctx), p.transationID())
 	if err := os.MkdirAll(dir, executableMode); err != nil {
 		log.Fatal(unexpected(err))
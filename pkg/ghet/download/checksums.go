@@ -17,7 +17,7 @@ import (
 	"strings"
 
 	"emperror.dev/errors"
-	githubapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/gookit/color"
 	"knative.dev/client/pkg/output/logging"
 	"knative.dev/client/pkg/output/tui"
@@ -43,35 +43,35 @@ var ErrInvalidChecksumLine = errors.New("invalid checksum line")
 
 var bsdStyleChecksums = regexp.MustCompile(`^(SHA[0-9]{1,3})\s+\(([^)]+)\)\s+=\s+([a-fA-F0-9]{32,128})$`)
 
-func (p Plan) verifyChecksums(ctx context.Context) error {
+func (p Plan) verifyChecksums(ctx context.Context) (ChecksumStatus, error) {
 	widgets := tui.NewWidgets(ctx)
 	cs, err := p.newChecksumVerifier(ctx)
 	if err != nil {
 		if errors.Is(err, ErrNoChecksum) {
 			widgets.Printf("⚠️ No checksums found. Skipping verification")
-			return nil
+			return ChecksumsSkipped, nil
 		}
-		return err
+		return "", err
 	}
 
-	index := githubapi.CreateIndex(p.Assets)
-	artifacts := make([]githubapi.Asset, 0, len(index.Archives)+len(index.Binaries))
+	index := p.index()
+	artifacts := make([]artifact.Asset, 0, len(index.Archives)+len(index.Binaries))
 	artifacts = append(append(artifacts, index.Archives...), index.Binaries...)
-	err = cs.verify(ctx, artifacts, func(curr githubapi.Asset) string {
+	err = cs.verify(ctx, artifacts, func(curr artifact.Asset) string {
 		return path.Dir(p.cachePath(ctx, curr))
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	widgets.Printf("✅ All checksums match the downloaded assets")
 
-	return nil
+	return ChecksumsVerified, nil
 }
 
 func (p Plan) newChecksumVerifier(ctx context.Context) (*checksumVerifier, error) {
 	l := logging.LoggerFrom(ctx)
-	index := githubapi.CreateIndex(p.Assets)
+	index := p.index()
 	if len(index.Checksums) == 0 {
 		l.Debug("No checksums to verify")
 		return nil, ErrNoChecksum
@@ -88,7 +88,7 @@ func (p Plan) newChecksumVerifier(ctx context.Context) (*checksumVerifier, error
 			}
 			return nil, unexpected(err)
 		}
-		chooser := tui.NewChooser[githubapi.Asset](iwidgets)
+		chooser := tui.NewChooser[artifact.Asset](iwidgets)
 		selected := chooser.Choose(index.Checksums,
 			"⚠️ More than one checksum file found. Choose proper one")
 		for _, c := range index.Checksums {
@@ -98,7 +98,7 @@ func (p Plan) newChecksumVerifier(ctx context.Context) (*checksumVerifier, error
 			}
 		}
 	}
-	artifacts := make([]githubapi.Asset, 0, len(index.Archives)+len(index.Binaries))
+	artifacts := make([]artifact.Asset, 0, len(index.Archives)+len(index.Binaries))
 	artifacts = append(append(artifacts, index.Archives...), index.Binaries...)
 	if len(artifacts) == 0 {
 		l.Errorf("No assets to verify")
@@ -117,7 +117,7 @@ func (p Plan) newChecksumVerifier(ctx context.Context) (*checksumVerifier, error
 }
 
 type checksumParser struct {
-	githubapi.Asset
+	artifact.Asset
 	plan *Plan
 	*checksumVerifier
 }
@@ -270,7 +270,7 @@ func (e checksumEntry) Matches(name string) bool {
 	return e.filename == "-" || e.filename == name
 }
 
-func (e checksumEntry) verify(asset githubapi.Asset, dest string) error {
+func (e checksumEntry) verify(asset artifact.Asset, dest string) error {
 	dig := e.newDigest()
 	fp := path.Join(dest, asset.Name)
 	var reader io.Reader
@@ -296,8 +296,8 @@ type checksumVerifier struct {
 }
 
 func (c checksumVerifier) verify(
-	ctx context.Context, assets []githubapi.Asset,
-	dirFn func(curr githubapi.Asset) string,
+	ctx context.Context, assets []artifact.Asset,
+	dirFn func(curr artifact.Asset) string,
 ) error {
 	widgets := tui.NewWidgets(ctx)
 	for _, entry := range c.entries {
@@ -0,0 +1,71 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"emperror.dev/errors"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+// ErrDigestMismatch is returned when the downloaded asset doesn't match its
+// expected digest.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// digests calculates the SHA-256 digests of the downloaded assets.
+func (p Plan) digests(ctx context.Context) (map[string]string, error) {
+	digests := make(map[string]string, len(p.Assets))
+	for _, asset := range p.Assets {
+		dig, err := sha256Of(p.cachePath(ctx, asset))
+		if err != nil {
+			return nil, err
+		}
+		digests[asset.Name] = dig
+	}
+	return digests, nil
+}
+
+// verifyDigests checks that the downloaded assets are exactly the expected
+// ones, and match their digests.
+func verifyDigests(ctx context.Context, expected, actual map[string]string) error {
+	l := logging.LoggerFrom(ctx)
+	for name := range expected {
+		if _, ok := actual[name]; !ok {
+			return errors.WithStack(fmt.Errorf("%w: %s isn't downloaded",
+				ErrDigestMismatch, name))
+		}
+	}
+	for name, dig := range actual {
+		want, ok := expected[name]
+		if !ok {
+			return errors.WithStack(fmt.Errorf("%w: %s isn't expected",
+				ErrDigestMismatch, name))
+		}
+		if want != dig {
+			return errors.WithStack(fmt.Errorf("%w: %s, %s != %s",
+				ErrDigestMismatch, name, dig, want))
+		}
+		l.WithFields(logging.Fields{"asset": name, "sha256": dig}).
+			Debug("Digest matches")
+	}
+	tui.NewWidgets(ctx).Printf("🔒 All assets match the locked digests")
+	return nil
+}
+
+func sha256Of(fp string) (string, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return "", unexpected(err)
+	}
+	defer f.Close()
+	dig := sha256.New()
+	if _, err = io.Copy(dig, f); err != nil {
+		return "", unexpected(err)
+	}
+	return hex.EncodeToString(dig.Sum(nil)), nil
+}
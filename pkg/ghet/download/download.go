@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 
 	"emperror.dev/errors"
-	githubapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/provider"
+	"golang.org/x/sync/errgroup"
 	"knative.dev/client/pkg/output/logging"
 	"knative.dev/client/pkg/output/tui"
 )
@@ -18,62 +21,154 @@ const (
 	executableMode = 0o750
 )
 
-type assetInfo struct {
-	githubapi.Asset
-	number      int
-	total       int
-	longestName int
+// downloadAssets downloads the assets concurrently, up to the parallel limit,
+// rendering a combined progress of all of them. The first error cancels the
+// remaining downloads.
+func (p Plan) downloadAssets(ctx context.Context, args Args) error {
+	l := logging.LoggerFrom(ctx)
+	prov, err := provider.ForSite(ctx, args.Site, args.Asset)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	p.resolveSizes(ctx, prov)
+	pending := make([]artifact.Asset, 0, len(p.Assets))
+	total, known := 0, true
+	for _, asset := range p.Assets {
+		cachePath := p.cachePath(ctx, asset)
+		if asset.Size > 0 && fileExists(l, cachePath, asset.Size) {
+			l.WithFields(logging.Fields{"cachePath": cachePath}).
+				Debug("Asset already downloaded")
+			continue
+		}
+		pending = append(pending, asset)
+		known = known && asset.Size > 0
+		total += asset.Size
+		if part := loadPartial(cachePath); part.resumable(asset.Size) {
+			total -= int(part.size)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(pending))
+	for _, asset := range pending {
+		names = append(names, asset.Name)
+	}
+	download := func(progress io.Writer) error {
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(args.parallel())
+		for _, asset := range pending {
+			asset := asset
+			g.Go(func() error {
+				if err := gctx.Err(); err != nil {
+					// Another download has failed already.
+					return err //nolint:wrapcheck
+				}
+				return p.downloadAsset(gctx, prov, asset, progress)
+			})
+		}
+		return g.Wait() //nolint:wrapcheck
+	}
+	widgets := tui.NewWidgets(ctx)
+	text := fmt.Sprintf("📥 %s", strings.Join(names, ", "))
+	if !known {
+		// The progress can't be rendered without knowing the total size.
+		return widgets.NewSpinner(text).With(func(_ tui.SpinnerControl) error { //nolint:wrapcheck
+			return download(io.Discard)
+		})
+	}
+	progress := widgets.NewProgress(total, tui.Message{Text: text})
+	return progress.With(func(pc tui.ProgressControl) error { //nolint:wrapcheck
+		if err := download(&syncWriter{w: pc}); err != nil {
+			pc.Error(err)
+			return err
+		}
+		return nil
+	})
+}
+
+// resolveSizes asks for the sizes of the assets the site doesn't report, so
+// the cached assets can be recognized, and the progress rendered.
+func (p Plan) resolveSizes(ctx context.Context, prov provider.ReleaseProvider) {
+	l := logging.LoggerFrom(ctx)
+	for i := range p.Assets {
+		asset := &p.Assets[i]
+		if asset.Size > 0 {
+			continue
+		}
+		size, err := prov.AssetSize(ctx, *asset)
+		if err != nil {
+			l.WithFields(logging.Fields{"asset": asset.Name}).
+				Debugf("Can't resolve the asset size: %v", err)
+			continue
+		}
+		asset.Size = size
+	}
 }
 
-func (p Plan) downloadAsset(ctx context.Context, asset assetInfo) error {
+// downloadAsset downloads the asset into the cache. An interrupted download is
+// kept, and resumed with a range request the next time, unless the asset has
+// changed since, or the server doesn't support ranges.
+func (p Plan) downloadAsset(
+	ctx context.Context, prov provider.ReleaseProvider,
+	asset artifact.Asset, progress io.Writer,
+) error {
 	l := logging.LoggerFrom(ctx).WithFields(logging.Fields{
 		"asset": asset.Name,
 	})
-	cachePath := p.cachePath(ctx, asset.Asset)
-
-	if fileExists(l, cachePath, asset.Size) {
-		l.WithFields(logging.Fields{"cachePath": cachePath}).
-			Debug("Asset already downloaded")
-		return nil
-	}
+	part := loadPartial(p.cachePath(ctx, asset))
 
 	l.Debug("Downloading asset")
-	cl := githubapi.FromContext(ctx).Client()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	resp, err := prov.OpenAsset(ctx, asset, part.header(asset.Size))
 	if err != nil {
-		return errors.WithStack(err)
-	}
-	resp, err := cl.Do(req)
-	if err != nil {
-		return errors.WithStack(err)
+		return err //nolint:wrapcheck
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	resumed := part.resumes(resp)
+	switch {
+	case resumed:
+		l.WithFields(logging.Fields{"offset": part.size}).
+			Debug("Resuming partial download")
+	case resp.StatusCode == http.StatusOK:
+		if part.size > 0 {
+			l.Debug("Partial download can't be resumed, starting over")
+		}
+		if err = part.restart(resp); err != nil {
+			return err
+		}
+	default:
+		if part.size > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			part.discard()
+		}
 		return fmt.Errorf("%w: unexpected status code: %d",
 			ErrNoAssetFound, resp.StatusCode)
 	}
 
-	out, err := os.Create(cachePath)
+	out, err := part.open(resumed)
 	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, io.TeeReader(resp.Body, progress)); err != nil {
+		_ = out.Close()
 		return errors.WithStack(err)
 	}
-	defer out.Close()
+	if err = out.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return part.complete()
+}
 
-	format := "📥 %d%d %s"
+// syncWriter serializes the writes of concurrent downloads.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
 
-	progress := tui.NewWidgets(ctx).NewProgress(asset.Size, tui.Message{
-		Text:        fmt.Sprintf(format, asset.number, asset.total, asset.Name),
-		PaddingSize: len(fmt.Sprintf(format, asset.total, asset.total, strings.Repeat("x", asset.longestName))),
-	})
-	return progress.With(func(pc tui.ProgressControl) error { //nolint:wrapcheck
-		_, err = io.Copy(out, io.TeeReader(resp.Body, pc))
-		if err != nil {
-			pc.Error(err)
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p) //nolint:wrapcheck
 }
 
 func fileExists(l logging.Logger, path string, size int) bool {
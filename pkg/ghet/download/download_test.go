@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	configdir "github.com/cardil/ghet/pkg/config/dir"
 	"github.com/cardil/ghet/pkg/ghet/download"
 	"github.com/cardil/ghet/pkg/ghet/install"
@@ -40,6 +41,7 @@ func TestDownload(t *testing.T) {
 			name: "minikube",
 			size: 42,
 		}},
+		wantChecksums: download.ChecksumsVerifiedInArchive,
 	}, {
 		name: "sharkdp/diskus",
 		args: downloadArgs{
@@ -52,6 +54,23 @@ func TestDownload(t *testing.T) {
 			name: "diskus",
 			size: 40,
 		}},
+		wantChecksums: download.ChecksumsSkipped,
+	}, {
+		name: "sharkdp/diskus",
+		args: downloadArgs{
+			name: "diskus",
+			assets: []string{
+				"diskus-v0.7.0-x86_64-unknown-linux-gnu.tar.gz",
+				"diskus-v0.7.0-checksums.txt",
+			},
+			// The checksums are of the archive, so none match the binary.
+			verifyInArchive: true,
+		},
+		want: []downloaded{{
+			name: "diskus",
+			size: 40,
+		}},
+		wantChecksums: download.ChecksumsSkipped,
 	}, {
 		name: "pulumi/pulumi",
 		args: downloadArgs{
@@ -67,6 +86,7 @@ func TestDownload(t *testing.T) {
 			{name: "pulumi-watch", size: 49},
 			{name: "pulumi-language-go", size: 54},
 		},
+		wantChecksums: download.ChecksumsVerified,
 	}, {
 		name: "knative-sandbox/kn-plugin-event",
 		args: downloadArgs{
@@ -80,6 +100,7 @@ func TestDownload(t *testing.T) {
 			name: "kn-event",
 			size: 42,
 		}},
+		wantChecksums: download.ChecksumsVerified,
 	}, {
 		name: "asciinema/agg",
 		args: downloadArgs{
@@ -92,6 +113,20 @@ func TestDownload(t *testing.T) {
 			name: "agg",
 			size: 37,
 		}},
+		wantChecksums: download.ChecksumsSkipped,
+	}, {
+		name: "pulumi/pulumi",
+		args: downloadArgs{
+			name: "pulumi",
+			assets: []string{
+				"pulumi-v3.71.0-linux-x64.tar.gz",
+				"pulumi-3.71.0-checksums.txt",
+			},
+			missing:      []string{"pulumi-v3.71.0-linux-x64.tar.gz.sig"},
+			multipleBins: true,
+			parallel:     2,
+		},
+		wantErr: download.ErrNoAssetFound,
 	}}
 	for _, tc := range tcs {
 		t.Run(tc.name, tc.run)
@@ -112,8 +147,12 @@ func (tc downloadTestCase) run(t *testing.T) {
 		wd := t.TempDir()
 		plan := tc.buildPlan(t, client.BaseURL)
 		args := tc.buildArgs(wd)
-		err := plan.Download(ctx, args)
+		res, err := plan.Download(ctx, args)
 		assert.ErrorIs(t, err, tc.wantErr, "%+v", err)
+		if err == nil {
+			assert.Len(t, res.Binaries, len(tc.want))
+			assert.Equal(t, tc.wantChecksums, res.Verification.Checksums)
+		}
 		for _, d := range tc.want {
 			fp := path.Join(wd, d.name)
 			var fi os.FileInfo
@@ -148,7 +187,7 @@ func (tc downloadTestCase) configureMux(mux *http.ServeMux) {
 
 func (tc downloadTestCase) buildPlan(t testingT, baseurl *url.URL) download.Plan {
 	p := download.Plan{
-		Assets: make([]ghapi.Asset, 0, len(tc.args.assets)),
+		Assets: make([]artifact.Asset, 0, len(tc.args.assets)),
 	}
 	// Deterministic random numbers.
 	rnd := rand.New(rand.NewSource(465712)) //nolint:gosec
@@ -159,7 +198,7 @@ func (tc downloadTestCase) buildPlan(t testingT, baseurl *url.URL) download.Plan
 		require.NoError(t, err)
 		require.NoError(t, f.Close())
 
-		p.Assets = append(p.Assets, ghapi.Asset{
+		p.Assets = append(p.Assets, artifact.Asset{
 			ID:          rnd.Int63(),
 			Name:        asset,
 			ContentType: "application/octet-stream",
@@ -167,6 +206,15 @@ func (tc downloadTestCase) buildPlan(t testingT, baseurl *url.URL) download.Plan
 			URL:         baseurl.String() + asset,
 		})
 	}
+	for _, asset := range tc.args.missing {
+		p.Assets = append(p.Assets, artifact.Asset{
+			ID:          rnd.Int63(),
+			Name:        asset,
+			ContentType: "application/octet-stream",
+			Size:        1,
+			URL:         baseurl.String() + asset,
+		})
+	}
 	return p
 }
 
@@ -174,7 +222,7 @@ func (tc downloadTestCase) buildArgs(wd string) download.Args {
 	fields := strings.FieldsFunc(tc.name, func(r rune) bool {
 		return r == '/'
 	})
-	repo := pkggithub.Repository{
+	repo := artifact.Repository{
 		Owner: fields[0],
 		Repo:  fields[1],
 	}
@@ -192,14 +240,18 @@ func (tc downloadTestCase) buildArgs(wd string) download.Args {
 			VerifyInArchive:  tc.args.verifyInArchive,
 		},
 		Destination: wd,
+		Parallel:    tc.args.parallel,
 	}
 }
 
 type downloadArgs struct {
-	name            string
-	assets          []string
+	name   string
+	assets []string
+	// missing assets are planned, but not served.
+	missing         []string
 	multipleBins    bool
 	verifyInArchive bool
+	parallel        int
 }
 
 type downloaded struct {
@@ -211,8 +263,9 @@ type downloadTestCase struct {
 	name string
 	args downloadArgs
 
-	want    []downloaded
-	wantErr error
+	want          []downloaded
+	wantChecksums download.ChecksumStatus
+	wantErr       error
 }
 
 func isExecutable(mode os.FileMode) bool {
@@ -0,0 +1,81 @@
+package download
+
+import (
+	"context"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"knative.dev/client/pkg/output/logging"
+)
+
+// Explanation tells how the assets of the release were matched.
+type Explanation struct {
+	Repository      artifact.Repository       `json:"repository"`
+	Tag             string                    `json:"tag"`
+	BaseName        string                    `json:"basename"`
+	Architecture    pkggithub.Architecture    `json:"architecture"`
+	OperatingSystem pkggithub.OperatingSystem `json:"operatingSystem"`
+	// Targeted is set if the release was rendered for the target, so its
+	// assets are taken as they are, without matching.
+	Targeted bool             `json:"targeted"`
+	Assets   []ExplainedAsset `json:"assets"`
+}
+
+// ExplainedAsset is an asset of the release, with the verdicts of the
+// matchers.
+type ExplainedAsset struct {
+	pkggithub.MatchExplanation
+	Category AssetCategory `json:"category"`
+	// Score ranks the asset among the other matching ones.
+	Score int `json:"score"`
+	// Dropped is set if the asset matched, but another one was preferred.
+	Dropped bool `json:"dropped"`
+	// Chosen is set if the asset would be downloaded.
+	Chosen bool `json:"chosen"`
+}
+
+// Explain fetches the release, and tells how each of its assets is matched,
+// without downloading anything.
+func Explain(ctx context.Context, args Args) (*Explanation, error) {
+	ctx = logging.EnsureLogger(ctx, logging.Fields{
+		"owner": args.Owner,
+		"repo":  args.Repo,
+	})
+	args = args.withRules(ctx)
+	prov, err := provider.ForSite(ctx, args.Site, args.Asset)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	rel, err := fetchRelease(ctx, args, prov)
+	if err != nil {
+		return nil, err
+	}
+	chosen := make(map[string]bool, len(rel.Assets))
+	for _, a := range selectAssets(ctx, args, rel) {
+		chosen[a.Name] = true
+	}
+	assets := make([]ExplainedAsset, 0, len(rel.Assets))
+	for _, a := range rel.Assets {
+		e := args.Explain(a.Name)
+		if rel.Targeted {
+			e.Matches = true
+		}
+		assets = append(assets, ExplainedAsset{
+			MatchExplanation: e,
+			Category:         categoryOf(a, checksumNames(args)),
+			Score:            args.Score(a.Name),
+			Dropped:          e.Matches && !chosen[a.Name],
+			Chosen:           chosen[a.Name],
+		})
+	}
+	return &Explanation{
+		Repository:      args.Repository,
+		Tag:             rel.Tag,
+		BaseName:        args.BaseName,
+		Architecture:    args.Architecture,
+		OperatingSystem: args.OperatingSystem,
+		Targeted:        rel.Targeted,
+		Assets:          assets,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package download_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "derailed", Repo: "k9s"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v0.32.5",
+		Assets: map[string][]byte{
+			"k9s_Linux_amd64.tar.gz":  nil,
+			"k9s_linux_amd64":         nil,
+			"k9s_linux_amd64.deb":     nil,
+			"k9s_Darwin_arm64.tar.gz": nil,
+			"checksums.txt":           nil,
+		},
+	}))
+	args := download.Args{
+		Args: install.Args{
+			Asset: pkggithub.Asset{
+				FileName:        pkggithub.FileName{BaseName: "k9s"},
+				Architecture:    pkggithub.ArchAMD64,
+				OperatingSystem: pkggithub.OSLinuxGnu,
+				Release:         pkggithub.Release{Tag: pkggithub.LatestTag, Repository: repo},
+			},
+		},
+	}
+
+	ex, err := download.Explain(ctx, args)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v0.32.5", ex.Tag)
+	assets := make(map[string]download.ExplainedAsset, len(ex.Assets))
+	for _, a := range ex.Assets {
+		assets[a.Name] = a
+	}
+	require.Len(t, assets, 5)
+	assert.True(t, assets["k9s_Linux_amd64.tar.gz"].Chosen)
+	assert.Equal(t, download.CategoryArchive, assets["k9s_Linux_amd64.tar.gz"].Category)
+	assert.True(t, assets["checksums.txt"].Chosen)
+	assert.True(t, assets["checksums.txt"].Checksum)
+
+	bin := assets["k9s_linux_amd64"]
+	assert.True(t, bin.Matches)
+	assert.True(t, bin.Dropped)
+	assert.False(t, bin.Chosen)
+
+	deb := assets["k9s_linux_amd64.deb"]
+	assert.True(t, deb.PackageManager)
+	assert.True(t, deb.Architecture)
+	assert.False(t, deb.OperatingSystem)
+	assert.False(t, deb.Matches)
+
+	darwin := assets["k9s_Darwin_arm64.tar.gz"]
+	assert.True(t, darwin.BaseName)
+	assert.Equal(t, "darwin_arm64.tar.gz", darwin.Coordinates)
+	assert.False(t, darwin.Architecture)
+	assert.False(t, darwin.OperatingSystem)
+	assert.False(t, darwin.Chosen || darwin.Dropped)
+}
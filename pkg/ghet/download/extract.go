@@ -9,31 +9,37 @@ import (
 	"io/fs"
 	"os"
 	"path"
-	"strings"
 
-	githubapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/gookit/color"
 	"github.com/mholt/archiver/v4"
 	"knative.dev/client/pkg/output/logging"
 	"knative.dev/client/pkg/output/tui"
 )
 
-func (p Plan) extractArchives(ctx context.Context, args Args) error {
+// extractArchives extracts the binaries from the archives of the plan. It
+// tells whether every extracted binary was verified against the checksums.
+func (p Plan) extractArchives(ctx context.Context, args Args) ([]string, bool, error) {
 	widgets := tui.NewWidgets(ctx)
-	index := githubapi.CreateIndex(p.Assets)
+	index := p.index()
+	extracted := make([]string, 0, len(index.Archives))
+	verified := len(index.Archives) > 0
 	for _, asset := range index.Archives {
 		widgets.Printf("📦 Extracting archive: %s", color.Cyan.Sprintf(asset.Name))
 		ar := archiveAsset{Asset: asset, plan: &p}
 		lctx := logging.EnsureLogger(ctx, logging.Fields{"asset": asset.Name})
-		if err := ar.extract(lctx, args); err != nil {
-			return err
+		binaries, ok, err := ar.extract(lctx, args)
+		if err != nil {
+			return nil, false, err
 		}
+		extracted = append(extracted, binaries...)
+		verified = verified && ok
 	}
-	return nil
+	return extracted, verified, nil
 }
 
 type archiveAsset struct {
-	githubapi.Asset
+	artifact.Asset
 	plan *Plan
 }
 
@@ -50,42 +56,52 @@ func (aa archiveAsset) open(ctx context.Context) (fs.FS, error) {
 	return fsys, nil
 }
 
-func (aa archiveAsset) extract(ctx context.Context, args Args) error {
+func (aa archiveAsset) extract(ctx context.Context, args Args) ([]string, bool, error) {
 	fsys, err := aa.open(ctx)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	var binaries []compressedBinary
 	if binaries, err = findBinaries(ctx, args, fsys); err != nil {
-		return err
+		return nil, false, err
 	}
 
 	if binaries, err = chooseBinaries(ctx, args, binaries); err != nil {
-		return err
+		return nil, false, err
 	}
 
 	var cv *checksumVerifier
 	if args.VerifyInArchive {
 		if cv, err = aa.plan.newChecksumVerifier(ctx); err != nil {
-			return err
+			return nil, false, err
 		}
 	}
 
+	extracted := make([]string, 0, len(binaries))
+	verified := len(binaries) > 0
 	for _, binary := range binaries {
-		if err = extractBinary(ctx, args, fsys, binary, cv); err != nil {
-			return err
+		var (
+			binaryPath string
+			ok         bool
+		)
+		if binaryPath, ok, err = extractBinary(ctx, args, fsys, binary, cv); err != nil {
+			return nil, false, err
 		}
+		extracted = append(extracted, binaryPath)
+		verified = verified && ok
 	}
 
-	return nil
+	return extracted, verified, nil
 }
 
+// extractBinary extracts the binary from the archive. It tells whether the
+// binary was compared with its checksum.
 func extractBinary(
 	ctx context.Context, args Args,
 	fsys fs.FS, binary compressedBinary,
 	cv *checksumVerifier,
-) error {
+) (string, bool, error) {
 	var (
 		ff  fs.File
 		fi  fs.FileInfo
@@ -93,10 +109,10 @@ func extractBinary(
 	)
 	widgets := tui.NewWidgets(ctx)
 	if fi, err = archiver.TopDirStat(fsys, binary.path); err != nil {
-		return unexpected(err)
+		return "", false, unexpected(err)
 	}
 	if ff, err = archiver.TopDirOpen(fsys, binary.path); err != nil {
-		return unexpected(err)
+		return "", false, unexpected(err)
 	}
 	defer ff.Close()
 
@@ -110,23 +126,24 @@ func extractBinary(
 	}
 	hp := hashPair{}
 	if err = extractToBinaryPath(binaryPath, args, cv, binary, progress, ff, &hp); err != nil {
-		return err
+		return "", false, err
 	}
 
-	if hp.actual != nil {
+	verified := hp.actual != nil
+	if verified {
 		actualHash := hex.EncodeToString(hp.actual.Sum(nil))
 		if hp.expect != actualHash {
-			return fmt.Errorf("%w: %s != %s", ErrChecksumMismatch,
+			return "", false, fmt.Errorf("%w: %s != %s", ErrChecksumMismatch,
 				hp.expect, actualHash)
 		}
 		widgets.Printf("✅ Checksum match the extracted binary")
 	}
 
 	if err = os.Chmod(binaryPath, fi.Mode()); err != nil {
-		return unexpected(err)
+		return "", false, unexpected(err)
 	}
 
-	return nil
+	return binaryPath, verified, nil
 }
 
 type hashPair struct {
@@ -189,12 +206,11 @@ func findBinaries(ctx context.Context, args Args, fsys fs.FS) ([]compressedBinar
 		}
 		l.WithFields(logging.Fields{"type": d.Type().Perm()}).
 			Debugf("Checking in-archive file: %s", p)
-		filename := d.Name()
 		var fi fs.FileInfo
 		if fi, err = d.Info(); err != nil {
 			return unexpected(err)
 		}
-		if !d.IsDir() && isExecutable(fi.Mode().Perm()) && strings.Contains(filename, args.BaseName) {
+		if !d.IsDir() && isExecutable(fi.Mode().Perm()) && args.isBinary(p) {
 			binaries = append(binaries, compressedBinary{p, fi})
 		}
 		return nil
@@ -0,0 +1,191 @@
+//go:build !race
+
+package download_test
+
+import (
+	gocontext "context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+)
+
+func TestDownloadWithFakeProvider(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	ctx := context.TestContext(t)
+	ctx = configdir.WithCacheDir(ctx, tmpDir)
+	ctx = configdir.WithConfigDir(ctx, tmpDir)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "cardil", Repo: "ghet"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v0.5.0",
+		Assets: map[string][]byte{
+			"ghet-linux-amd64":  []byte("linux"),
+			"ghet-darwin-arm64": []byte("darwin"),
+		},
+	}))
+	wd := t.TempDir()
+	args := download.Args{
+		Args: install.Args{
+			Asset: pkggithub.Asset{
+				FileName:        pkggithub.FileName{BaseName: "ghet"},
+				Architecture:    pkggithub.ArchAMD64,
+				OperatingSystem: pkggithub.OSLinuxGnu,
+				Release:         pkggithub.Release{Tag: pkggithub.LatestTag, Repository: repo},
+			},
+		},
+		Destination: wd,
+	}
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	require.Len(t, plan.Assets, 1)
+	res, err := plan.Download(ctx, args)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.5.0", res.Tag)
+	assert.Equal(t, repo, res.Repository)
+	assert.Equal(t, download.Verification{
+		Checksums: download.ChecksumsSkipped,
+	}, res.Verification)
+	bytes, err := os.ReadFile(path.Join(wd, "ghet"))
+	require.NoError(t, err)
+	assert.Equal(t, "linux", string(bytes))
+}
+
+func TestDownloadCancelsOnFailure(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	ctx := context.TestContext(t)
+	ctx = configdir.WithCacheDir(ctx, tmpDir)
+	ctx = configdir.WithConfigDir(ctx, tmpDir)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "cardil", Repo: "ghet"}
+	prov := &stallingProvider{
+		Provider: fake.New().Add(repo, fake.Release{
+			Tag: "v0.5.0",
+			Assets: map[string][]byte{
+				"a-slow":   []byte("slow"),
+				"b-failed": []byte("failed"),
+				"c-queued": []byte("queued"),
+				"d-queued": []byte("queued"),
+			},
+		}),
+		failing:     "b-failed",
+		slowStarted: make(chan struct{}),
+	}
+	ctx = provider.WithContext(ctx, prov)
+	rel, err := prov.ReleaseByTag(ctx, repo, "v0.5.0")
+	require.NoError(t, err)
+	args := download.Args{
+		Args: install.Args{
+			Asset: pkggithub.Asset{
+				FileName: pkggithub.FileName{BaseName: "ghet"},
+				Release:  pkggithub.Release{Tag: rel.Tag, Repository: repo},
+			},
+		},
+		Destination: t.TempDir(),
+		Parallel:    2,
+	}
+	plan := download.Plan{Tag: rel.Tag, Assets: rel.Assets}
+
+	_, err = plan.Download(ctx, args)
+
+	require.ErrorIs(t, err, download.ErrNoAssetFound)
+	assert.True(t, prov.slowCanceled.Load(), "the slow download is canceled")
+	assert.Equal(t, 2, prov.peak, "downloads at once")
+	assert.Equal(t, []string{"a-slow", "b-failed"}, prov.openedNames(),
+		"the queued downloads aren't started")
+}
+
+// stallingProvider serves a slow asset, that is downloaded until canceled,
+// and a failing one, that fails once the slow download has started.
+type stallingProvider struct {
+	*fake.Provider
+	failing      string
+	slowStarted  chan struct{}
+	slowCanceled atomic.Bool
+	mu           sync.Mutex
+	active, peak int
+	opened       []string
+}
+
+func (s *stallingProvider) OpenAsset(
+	ctx gocontext.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	s.mu.Lock()
+	s.opened = append(s.opened, asset.Name)
+	s.active++
+	s.peak = max(s.peak, s.active)
+	s.mu.Unlock()
+	resp, err := s.Provider.OpenAsset(ctx, asset, header)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	switch {
+	case asset.Name == s.failing:
+		<-s.slowStarted
+		resp.StatusCode = http.StatusInternalServerError
+		resp.Status = http.StatusText(resp.StatusCode)
+		resp.Body = closer{Reader: resp.Body, close: s.done}
+	case strings.HasSuffix(asset.Name, "-slow"):
+		close(s.slowStarted)
+		resp.Body = closer{Reader: stalled{ctx: ctx, canceled: &s.slowCanceled}, close: s.done}
+	default:
+		resp.Body = closer{Reader: resp.Body, close: s.done}
+	}
+	return resp, nil
+}
+
+func (s *stallingProvider) done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+}
+
+func (s *stallingProvider) openedNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := append([]string(nil), s.opened...)
+	sort.Strings(names)
+	return names
+}
+
+// stalled is a body that delivers nothing until the download is canceled.
+type stalled struct {
+	ctx      gocontext.Context
+	canceled *atomic.Bool
+}
+
+func (s stalled) Read([]byte) (int, error) {
+	<-s.ctx.Done()
+	s.canceled.Store(true)
+	return 0, s.ctx.Err() //nolint:wrapcheck
+}
+
+type closer struct {
+	io.Reader
+	close func()
+}
+
+func (c closer) Close() error {
+	c.close()
+	return nil
+}
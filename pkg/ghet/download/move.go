@@ -7,13 +7,13 @@ import (
 	"strings"
 
 	"github.com/1set/gut/yos"
-	githubapi "github.com/cardil/ghet/pkg/github/api"
 	"knative.dev/client/pkg/output/logging"
 )
 
-func (p Plan) moveBinaries(ctx context.Context, args Args) error {
+func (p Plan) moveBinaries(ctx context.Context, args Args) ([]string, error) {
 	l := logging.LoggerFrom(ctx)
-	index := githubapi.CreateIndex(p.Assets)
+	index := p.index()
+	moved := make([]string, 0, len(index.Binaries))
 	binaryName := args.ToString()
 	for _, binary := range index.Binaries {
 		if len(index.Binaries) > 1 {
@@ -23,14 +23,15 @@ func (p Plan) moveBinaries(ctx context.Context, args Args) error {
 		source := p.cachePath(ctx, binary)
 		target := path.Join(args.Destination, binaryName)
 		if err := yos.MoveFile(source, target); err != nil {
-			return unexpected(err)
+			return nil, unexpected(err)
 		}
 
 		if strings.Contains(binary.ContentType, "octet-stream") {
 			if err := os.Chmod(target, executableMode); err != nil {
-				return unexpected(err)
+				return nil, unexpected(err)
 			}
 		}
+		moved = append(moved, target)
 	}
-	return nil
+	return moved, nil
 }
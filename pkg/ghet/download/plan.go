@@ -6,9 +6,10 @@ import (
 	"os"
 
 	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+	"github.com/cardil/ghet/pkg/artifact"
 	pkggithub "github.com/cardil/ghet/pkg/github"
-	githubapi "github.com/cardil/ghet/pkg/github/api"
-	"github.com/google/go-github/v48/github"
+	"github.com/cardil/ghet/pkg/provider"
 	"github.com/gookit/color"
 	"knative.dev/client/pkg/output/logging"
 	"knative.dev/client/pkg/output/tui"
@@ -16,153 +17,351 @@ import (
 
 var ErrNoAssetFound = errors.New("no matching asset found")
 
+// ErrDraftRelease is returned when the requested release is a draft.
+var ErrDraftRelease = errors.New("release is a draft")
+
+// ErrNoMatchingRelease is returned when no release matches the version
+// constraint.
+var ErrNoMatchingRelease = errors.New("no release matches the version constraint")
+
+// ErrNoVersionedRelease is returned when the latest release, including the
+// pre-releases, is requested, but no release has a semantic version to
+// compare.
+var ErrNoVersionedRelease = errors.New("no release has a semantic version")
+
 type Plan struct {
-	Assets []githubapi.Asset
+	Tag    string
+	Assets []artifact.Asset
+	// checksums are the names of the checksum files, given explicitly.
+	checksums []string
 }
 
+// Result describes the outcome of the executed plan.
+type Result struct {
+	Repository artifact.Repository `json:"repository"`
+	Tag        string              `json:"tag"`
+	Assets     []artifact.Asset    `json:"assets"`
+	Binaries   []string            `json:"binaries"`
+	// Links are the links to the binaries, if they were installed.
+	Links []string `json:"links,omitempty"`
+	// Digests are the SHA-256 digests of the downloaded assets, keyed by the
+	// asset name.
+	Digests      map[string]string `json:"digests"`
+	Verification Verification      `json:"verification"`
+}
+
+// Verification tells how the downloaded assets were verified.
+type Verification struct {
+	// Digests is set if the assets matched the expected digests.
+	Digests   bool           `json:"digests"`
+	Checksums ChecksumStatus `json:"checksums"`
+}
+
+// ChecksumStatus tells whether the published checksums were verified.
+type ChecksumStatus string
+
+const (
+	// ChecksumsVerified is set when the downloaded assets match the checksums.
+	ChecksumsVerified ChecksumStatus = "verified"
+	// ChecksumsVerifiedInArchive is set when the binaries extracted from the
+	// archives match the checksums.
+	ChecksumsVerifiedInArchive ChecksumStatus = "verified-in-archive"
+	// ChecksumsSkipped is set when the release has no checksums, or none of
+	// them were compared with the assets or the extracted binaries.
+	ChecksumsSkipped ChecksumStatus = "skipped"
+)
+
 func CreatePlan(ctx context.Context, args Args) (*Plan, error) {
 	ctx = logging.EnsureLogger(ctx, logging.Fields{
 		"owner": args.Owner,
 		"repo":  args.Repo,
 	})
+	args = args.withRules(ctx)
 	log := logging.LoggerFrom(ctx)
-	client := githubapi.FromContext(ctx)
-	var (
-		rr  *github.RepositoryRelease
-		r   *github.Response
-		err error
-	)
+	prov, err := provider.ForSite(ctx, args.Site, args.Asset)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	var rel *provider.Release
 	widgets := tui.NewWidgets(ctx)
 	spin := widgets.NewSpinner(
 		fmt.Sprintf("⛳️ Getting information about %s release",
 			color.Cyan.Sprintf(args.Tag)),
 	)
 	if err = spin.With(func(_ tui.SpinnerControl) error {
-		rr, r, err = fetchRelease(ctx, args, client)
+		rel, err = fetchRelease(ctx, args, prov)
 		return err
 	}); err != nil {
 		return nil, err
 	}
 
-	log.WithFields(logging.Fields{
-		"response": r,
-		"release":  rr,
-	}).Debug("Github API response")
-
-	assets := make([]githubapi.Asset, 0, 1)
-	log.WithFields(logging.Fields{"assets": namesOf(rr.Assets)}).
-		Debug("Checking assets")
-	for _, asset := range rr.Assets {
-		if args.Matches(asset.GetName()) {
-			a := githubapi.Asset{
-				ID:          asset.GetID(),
-				Name:        asset.GetName(),
-				ContentType: asset.GetContentType(),
-				Size:        asset.GetSize(),
-				URL:         asset.GetBrowserDownloadURL(),
-			}
-			log.WithFields(logging.Fields{"asset": a}).Debug("Asset matches")
-			assets = append(assets, a)
-		}
+	log.WithFields(logging.Fields{"release": rel}).Debug("Release fetched")
+	if rel.PreRelease && !args.PreRelease {
+		widgets.Printf("⚠️ %s is a pre-release",
+			color.Yellow.Sprintf(rel.Tag))
 	}
-	index := githubapi.CreateIndex(assets)
-	assets = prioritizeArchives(index)
+
+	assets := selectAssets(ctx, args, rel)
 	if len(assets) == 0 {
 		return nil, errors.WithStack(ErrNoAssetFound)
 	}
-	plan := &Plan{Assets: assets}
+	plan := &Plan{Tag: rel.Tag, Assets: assets, checksums: checksumNames(args)}
 	log.WithFields(logging.Fields{"plan": plan}).Debug("Plan created")
 	widgets.Printf("🎉 Found %s matching assets for %s",
-		color.Cyan.Sprint(len(assets)), color.Cyan.Sprintf(rr.GetTagName()))
+		color.Cyan.Sprint(len(assets)), color.Cyan.Sprintf(rel.Tag))
 	return plan, nil
 }
 
-func (p Plan) Download(ctx context.Context, args Args) error {
+func (p Plan) Download(ctx context.Context, args Args) (*Result, error) {
 	ctx = logging.EnsureLogger(ctx, logging.Fields{
 		"owner": args.Owner,
 		"repo":  args.Repo,
 	})
-	longestName := 0
-
-	for _, asset := range p.Assets {
-		nameLen := len(asset.Name)
-		if nameLen > longestName {
-			longestName = nameLen
-		}
+	args = args.withRules(ctx)
+	if err := p.downloadAssets(ctx, args); err != nil {
+		return nil, err
 	}
-	for i, asset := range p.Assets {
-		ai := assetInfo{
-			Asset:       asset,
-			number:      i + 1,
-			total:       len(p.Assets),
-			longestName: longestName,
-		}
-		if err := p.downloadAsset(ctx, ai); err != nil {
-			return err
+	digests, err := p.digests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var verification Verification
+	if args.Digests != nil {
+		if err = verifyDigests(ctx, args.Digests, digests); err != nil {
+			return nil, err
 		}
+		verification.Digests = true
 	}
 	if !args.VerifyInArchive {
-		if err := p.verifyChecksums(ctx); err != nil {
-			return err
+		if verification.Checksums, err = p.verifyChecksums(ctx); err != nil {
+			return nil, err
 		}
 	}
 	if err := os.MkdirAll(args.Destination, executableMode); err != nil {
-		return unexpected(err)
+		return nil, unexpected(err)
 	}
-	if err := p.extractArchives(ctx, args); err != nil {
-		return err
+	extracted, verifiedInArchive, err := p.extractArchives(ctx, args)
+	if err != nil {
+		return nil, err
 	}
-	if err := p.moveBinaries(ctx, args); err != nil {
-		return err
+	if args.VerifyInArchive {
+		verification.Checksums = ChecksumsSkipped
+		if verifiedInArchive {
+			verification.Checksums = ChecksumsVerifiedInArchive
+		}
+	}
+	moved, err := p.moveBinaries(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.cleanCache(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Repository:   args.Repository,
+		Tag:          p.Tag,
+		Assets:       p.Assets,
+		Binaries:     append(extracted, moved...),
+		Digests:      digests,
+		Verification: verification,
+	}, nil
+}
+
+// selectAssets returns the assets of the release matching the arguments,
+// preferring the archives over the binaries.
+func selectAssets(ctx context.Context, args Args, rel *provider.Release) []artifact.Asset {
+	log := logging.LoggerFrom(ctx)
+	assets := make([]artifact.Asset, 0, 1)
+	log.WithFields(logging.Fields{"assets": namesOf(rel.Assets)}).
+		Debug("Checking assets")
+	for _, a := range rel.Assets {
+		if rel.Targeted || args.Matches(a.Name) {
+			log.WithFields(logging.Fields{"asset": a}).Debug("Asset matches")
+			assets = append(assets, a)
+		}
+	}
+	return prioritizeArchives(ctx, args,
+		artifact.CreateIndex(assets, checksumNames(args)...))
+}
+
+// index sorts the assets of the plan by their kinds.
+func (p Plan) index() artifact.IndexedAssets {
+	return artifact.CreateIndex(p.Assets, p.checksums...)
+}
+
+// checksumNames returns the name of the checksum file, if it was given.
+func checksumNames(args Args) []string {
+	if name := args.Checksums.ToString(); name != "" {
+		return []string{name}
 	}
+	return nil
+}
 
-	return p.cleanCache(ctx)
+// prioritizeArchives prefers the archives over the binaries. Of them, the
+// best scored one is picked, unless multiple binaries are wanted.
+func prioritizeArchives(
+	ctx context.Context, args Args, idx artifact.IndexedAssets,
+) []artifact.Asset {
+	candidates := idx.Binaries
+	if len(idx.Archives) > 0 {
+		candidates = idx.Archives
+	}
+	if !args.MultipleBinaries {
+		candidates = bestScored(ctx, args, candidates)
+	}
+	assets := make([]artifact.Asset, 0, len(candidates)+len(idx.Checksums))
+	assets = append(assets, candidates...)
+	return append(assets, idx.Checksums...)
+}
+
+// bestScored returns the asset with the highest score. The ties are broken
+// by the shorter, and then the lexically lower name, so the choice is
+// deterministic.
+func bestScored(ctx context.Context, args Args, assets []artifact.Asset) []artifact.Asset {
+	if len(assets) == 0 {
+		return assets
+	}
+	log := logging.LoggerFrom(ctx)
+	var (
+		best      artifact.Asset
+		bestScore int
+	)
+	for i, a := range assets {
+		score := args.Score(a.Name)
+		log.WithFields(logging.Fields{"asset": a.Name, "score": score}).
+			Debug("Asset scored")
+		if i == 0 || score > bestScore || score == bestScore &&
+			(len(a.Name) < len(best.Name) ||
+				len(a.Name) == len(best.Name) && a.Name < best.Name) {
+			best, bestScore = a, score
+		}
+	}
+	return []artifact.Asset{best}
 }
 
-func prioritizeArchives(idx githubapi.IndexedAssets) []githubapi.Asset {
-	if len(idx.Archives) > 0 && len(idx.Binaries) > 0 {
-		assets := make([]githubapi.Asset, 0, len(idx.Archives)+len(idx.Checksums))
-		assets = append(assets, idx.Archives...)
-		return append(assets, idx.Checksums...)
-	}
-	assets := make([]githubapi.Asset, 0, len(idx.Archives)+len(idx.Binaries)+len(idx.Checksums))
-	assets = append(assets, idx.Binaries...)
-	assets = append(assets, idx.Archives...)
-	assets = append(assets, idx.Checksums...)
-	return assets
+// ResolveTag resolves the tag of the release the given arguments point to,
+// without matching the assets.
+func ResolveTag(ctx context.Context, args Args) (string, error) {
+	ctx = logging.EnsureLogger(ctx, logging.Fields{
+		"owner": args.Owner,
+		"repo":  args.Repo,
+	})
+	prov, err := provider.ForSite(ctx, args.Site, args.Asset)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	rel, err := fetchRelease(ctx, args, prov)
+	if err != nil {
+		return "", err
+	}
+	return rel.Tag, nil
 }
 
-func namesOf(assets []*github.ReleaseAsset) []string {
+func namesOf(assets []artifact.Asset) []string {
 	names := make([]string, 0, len(assets))
 	for _, asset := range assets {
-		names = append(names, asset.GetName())
+		names = append(names, asset.Name)
 	}
 	return names
 }
 
 func fetchRelease(
-	ctx context.Context, args Args,
-	client *github.Client,
-) (*github.RepositoryRelease, *github.Response, error) {
+	ctx context.Context, args Args, prov provider.ReleaseProvider,
+) (*provider.Release, error) {
 	var (
 		err error
-		rr  *github.RepositoryRelease
-		r   *github.Response
+		rel *provider.Release
 	)
 	log := logging.LoggerFrom(ctx)
-	if args.Tag == pkggithub.LatestTag {
+	switch {
+	case args.Tag == pkggithub.LatestTag && args.PreRelease:
+		log.Debug("Getting latest release, including pre-releases")
+		return fetchHighestRelease(ctx, args, prov, nil)
+	case args.Tag == pkggithub.LatestTag:
 		log.Debug("Getting latest release")
-		if rr, r, err = client.Repositories.GetLatestRelease(ctx, args.Owner, args.Repo); err != nil {
-			return nil, nil, errors.WithStack(err)
+		if rel, err = prov.LatestRelease(ctx, args.Repository); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	case pkggithub.IsConstraint(args.Tag):
+		log.WithFields(logging.Fields{"constraint": args.Tag}).
+			Debug("Getting release matching constraint")
+		var constraint *semver.Constraints
+		if constraint, err = pkggithub.ParseConstraint(args.Tag); err != nil {
+			return nil, err
 		}
-		args.Tag = rr.GetTagName()
-	} else {
+		return fetchHighestRelease(ctx, args, prov, constraint)
+	default:
 		log.WithFields(logging.Fields{"tag": args.Tag}).
 			Debug("Getting release")
-		if rr, r, err = client.Repositories.GetReleaseByTag(ctx,
-			args.Owner, args.Repo, args.Tag); err != nil {
-			return nil, nil, errors.WithStack(err)
+		if rel, err = prov.ReleaseByTag(ctx, args.Repository, args.Tag); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+	if rel.Draft {
+		return nil, errors.WithStack(fmt.Errorf("%w: %s",
+			ErrDraftRelease, rel.Tag))
+	}
+	return rel, nil
+}
+
+// fetchHighestRelease lists the releases of the repository, and picks the
+// highest version that matches the constraint, if given. Drafts are never
+// picked, and pre-releases only if requested. In the latter case, the
+// pre-releases are matched by their core version, so 2.41.0-rc.1 matches ^2.40.
+func fetchHighestRelease(
+	ctx context.Context, args Args,
+	prov provider.ReleaseProvider, constraint *semver.Constraints,
+) (*provider.Release, error) {
+	var (
+		best    *provider.Release
+		bestVer *semver.Version
+	)
+	rels, err := prov.ListReleases(ctx, args.Repository)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	for i := range rels {
+		if v := acceptRelease(ctx, args, rels[i], constraint); v != nil &&
+			(bestVer == nil || v.GreaterThan(bestVer)) {
+			best, bestVer = &rels[i], v
 		}
 	}
-	return rr, r, nil
+	if best == nil && constraint == nil {
+		return nil, errors.WithStack(fmt.Errorf(
+			"%w: can't tell the latest release of %s, including pre-releases, "+
+				"give its tag instead", ErrNoVersionedRelease, args.Repository))
+	}
+	if best == nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %s",
+			ErrNoMatchingRelease, args.Tag))
+	}
+	return best, nil
+}
+
+func acceptRelease(
+	ctx context.Context, args Args,
+	rel provider.Release, constraint *semver.Constraints,
+) *semver.Version {
+	if rel.Draft || (rel.PreRelease && !args.PreRelease) {
+		return nil
+	}
+	v, err := pkggithub.ParseTagVersion(rel.Tag)
+	if err != nil {
+		logging.LoggerFrom(ctx).Debugf("Skipping release: %v", err)
+		return nil
+	}
+	if constraint == nil {
+		return v
+	}
+	core := *v
+	if v.Prerelease() != "" {
+		if core, err = v.SetPrerelease(""); err != nil {
+			return nil
+		}
+	}
+	if !constraint.Check(&core) {
+		return nil
+	}
+	return v
 }
@@ -4,15 +4,19 @@ import (
 	"embed"
 	"fmt"
 	"net/http"
+	"os"
 	"regexp"
 	"sort"
 	"testing"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/cardil/ghet/pkg/config"
 	"github.com/cardil/ghet/pkg/ghet/download"
 	"github.com/cardil/ghet/pkg/ghet/install"
 	"github.com/cardil/ghet/pkg/github"
 	ghapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
 	gh "github.com/google/go-github/v48/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,19 +25,35 @@ import (
 )
 
 var (
-	artifactRe = regexp.MustCompile("^([a-z0-9-]+)/([a-z0-9-]+)(?:@([a-z0-9._-]+))?(?:!!([a-z0-9._-]+))?$")
+	artifactRe = regexp.MustCompile("^([a-z0-9-]+)/([a-z0-9-]+)(?:@([a-z0-9._^~<>=,-]+))?(?:!!([a-z0-9._-]+))?$")
 	downloadRe = regexp.MustCompile("^https://github.com/([a-z0-9-]+)/([a-z0-9-]+)/releases/download/([a-z0-9._-]+)/(.+)$")
 )
 
 //go:embed testdata/*
 var fs embed.FS
 
+// TestMain keeps the tokens of the environment, and of the gh CLI, away from
+// the tests, as they change the URLs the assets are downloaded from.
+func TestMain(m *testing.M) {
+	for _, name := range append(ghapi.TokenEnvNames, ghapi.EnterpriseTokenEnvNames...) {
+		_ = os.Unsetenv(name)
+	}
+	dir, err := os.MkdirTemp("", "gh-config-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("GH_CONFIG_DIR", dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestCreatePlan(t *testing.T) {
 	t.Parallel()
 	testCases := []createPlanTestCase{{
 		name: "pulumi/pulumi",
 		want: result{version: "v3.69.0", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "pulumi-3.69.0-checksums.txt",
 				ContentType: "raw",
 				Size:        594,
@@ -46,7 +66,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "knative-sandbox/kn-plugin-event!!kn-event",
 		want: result{version: "knative-v1.9.1", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "checksums.txt",
 				ContentType: "text/plain",
 				Size:        615,
@@ -58,7 +78,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "knative-sandbox/kn-plugin-event@knative-v1.8.0!!kn-event",
 		want: result{version: "knative-v1.8.0", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name: "checksums.txt",
 				Size: 615,
 			}, {
@@ -69,7 +89,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "derailed/k9s",
 		want: result{version: "v0.27.3", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "checksums.txt",
 				Size:        896,
 				ContentType: "text/plain; charset=utf-8",
@@ -82,7 +102,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "kubernetes/minikube",
 		want: result{version: "v1.30.1", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name: "minikube-darwin-arm64.sha256",
 				Size: 65,
 			}, {
@@ -96,7 +116,7 @@ func TestCreatePlan(t *testing.T) {
 		arch: github.ArchAMD64,
 		os:   github.OSLinuxMusl,
 		want: result{version: "0.23.1", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "lsd-0.23.1-x86_64-unknown-linux-musl.tar.gz",
 				Size:        941_846,
 				ContentType: "application/gzip",
@@ -105,7 +125,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "marwanhawari/ppath",
 		want: result{version: "v0.0.3", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "checksums.txt",
 				Size:        394,
 				ContentType: "text/plain; charset=utf-8",
@@ -120,7 +140,7 @@ func TestCreatePlan(t *testing.T) {
 		arch: github.ArchAMD64,
 		os:   github.OSDarwin,
 		want: result{version: "v0.7.0", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "diskus-v0.7.0-x86_64-apple-darwin.tar.gz",
 				Size:        364_147,
 				ContentType: "application/gzip",
@@ -131,7 +151,7 @@ func TestCreatePlan(t *testing.T) {
 		arch: github.ArchX86,
 		os:   github.OSLinuxMusl,
 		want: result{version: "v0.9.0", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "pastel-v0.9.0-i686-unknown-linux-musl.tar.gz",
 				Size:        621_135,
 				ContentType: "application/gzip",
@@ -142,7 +162,7 @@ func TestCreatePlan(t *testing.T) {
 		arch: github.ArchAMD64,
 		os:   github.OSDarwin,
 		want: result{version: "v1.4.0", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "agg-x86_64-apple-darwin",
 				Size:        7_834_192,
 				ContentType: "binary/octet-stream",
@@ -151,7 +171,7 @@ func TestCreatePlan(t *testing.T) {
 	}, {
 		name: "golangci/golangci-lint",
 		want: result{version: "v1.52.2", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "golangci-lint-1.52.2-darwin-arm64.tar.gz",
 				Size:        9_866_130,
 				ContentType: "application/gzip",
@@ -166,18 +186,85 @@ func TestCreatePlan(t *testing.T) {
 		os:   github.OSLinuxGnu,
 		arch: github.ArchAMD64,
 		want: result{version: "v0.1.2", Plan: download.Plan{
-			Assets: []ghapi.Asset{{
+			Assets: []artifact.Asset{{
 				Name:        "linux-amd64-wasm-to-oci",
 				Size:        11_149_312,
 				ContentType: "application/octet-stream",
 			}},
 		}},
+	}, {
+		name:      "cli/cli@^2.40!!gh",
+		responses: listResponses,
+		want: result{version: "v2.40.1", Plan: download.Plan{
+			Assets: []artifact.Asset{{
+				Name:        "gh_2.40.1_checksums.txt",
+				Size:        1015,
+				ContentType: "text/plain; charset=utf-8",
+			}, {
+				Name:        "gh_2.40.1_macOS_arm64.zip",
+				Size:        10_872_112,
+				ContentType: "application/zip",
+			}},
+		}},
+	}, {
+		name:      "cli/cli@>=2.0,<2.40!!gh",
+		arch:      github.ArchAMD64,
+		os:        github.OSLinuxGnu,
+		responses: listResponses,
+		want: result{version: "v2.39.2", Plan: download.Plan{
+			Assets: []artifact.Asset{{
+				Name:        "gh_2.39.2_checksums.txt",
+				Size:        1015,
+				ContentType: "text/plain; charset=utf-8",
+			}, {
+				Name:        "gh_2.39.2_linux_amd64.tar.gz",
+				Size:        11_052_121,
+				ContentType: "application/gzip",
+			}},
+		}},
+	}, {
+		name:       "cli/cli@^2.40!!gh",
+		preRelease: true,
+		responses:  listResponses,
+		want: result{version: "v2.41.0-rc.1", Plan: download.Plan{
+			Assets: []artifact.Asset{{
+				Name:        "gh_2.41.0-rc.1_checksums.txt",
+				Size:        1015,
+				ContentType: "text/plain; charset=utf-8",
+			}, {
+				Name:        "gh_2.41.0-rc.1_macOS_arm64.zip",
+				Size:        10_872_112,
+				ContentType: "application/zip",
+			}},
+		}},
+	}, {
+		name:       "cli/cli!!gh",
+		preRelease: true,
+		responses:  listResponses,
+		want: result{version: "v3.1.0-beta.1", Plan: download.Plan{
+			Assets: []artifact.Asset{{
+				Name:        "gh_3.1.0-beta.1_checksums.txt",
+				Size:        1015,
+				ContentType: "text/plain; charset=utf-8",
+			}, {
+				Name:        "gh_3.1.0-beta.1_macOS_arm64.zip",
+				Size:        10_872_112,
+				ContentType: "application/zip",
+			}},
+		}},
 	}}
 	for _, tc := range testCases {
-		t.Run(tc.name, tc.performTest())
+		t.Run(tc.name+preReleaseSuffix(tc.preRelease), tc.performTest())
 	}
 }
 
+func preReleaseSuffix(preRelease bool) string {
+	if preRelease {
+		return "+prerelease"
+	}
+	return ""
+}
+
 type result struct {
 	version string
 	download.Plan
@@ -216,12 +303,13 @@ func (tc createPlanTestCase) resolve(t testingT) resolvedCreatePlanTestCase {
 	}
 	args := tc.args(t)
 	return resolvedCreatePlanTestCase{
-		args:      args,
-		arch:      tc.arch,
-		os:        tc.os,
-		want:      tc.want,
-		wantErr:   tc.wantErr,
-		responses: tc.responses(t, args),
+		args:       args,
+		arch:       tc.arch,
+		os:         tc.os,
+		preRelease: tc.preRelease,
+		want:       tc.want,
+		wantErr:    tc.wantErr,
+		responses:  tc.responses(t, args),
 	}
 }
 
@@ -243,13 +331,14 @@ func (tc resolvedCreatePlanTestCase) performTest(ctx context.Context, t testingT
 					OperatingSystem: tc.os,
 					Release: github.Release{
 						Tag: tc.args.tag,
-						Repository: github.Repository{
+						Repository: artifact.Repository{
 							Owner: tc.args.owner,
 							Repo:  tc.args.repo,
 						},
 					},
 				},
-				Site: config.Site{Type: config.TypeGitHub},
+				Site:       config.Site{Type: config.TypeGitHub},
+				PreRelease: tc.preRelease,
 			},
 			Destination: t.TempDir(),
 		}
@@ -276,21 +365,23 @@ type createPlanArgs struct {
 }
 
 type createPlanTestCase struct {
-	name    string
-	arch    github.Architecture
-	os      github.OperatingSystem
-	want    result
-	wantErr error
+	name       string
+	arch       github.Architecture
+	os         github.OperatingSystem
+	preRelease bool
+	want       result
+	wantErr    error
 	responses
 }
 
 type resolvedCreatePlanTestCase struct {
-	args      createPlanArgs
-	arch      github.Architecture
-	os        github.OperatingSystem
-	want      result
-	wantErr   error
-	responses []response
+	args       createPlanArgs
+	arch       github.Architecture
+	os         github.OperatingSystem
+	preRelease bool
+	want       result
+	wantErr    error
+	responses  []response
 }
 
 func get(uri, body string) response {
@@ -355,7 +446,9 @@ func (tc resolvedCreatePlanTestCase) simplify(t testingT, dp download.Plan) resu
 		m := downloadRe.FindStringSubmatch(dp.Assets[0].URL)
 		require.NotNilf(t, m, "invalid download url %q", dp.Assets[0].URL)
 		ver = m[3]
+		assert.Equal(t, ver, dp.Tag)
 	}
+	dp.Tag = ""
 	return result{
 		version: ver,
 		Plan:    dp,
@@ -434,3 +527,225 @@ func autoResponses(t testingT, args createPlanArgs) []response {
 		get(reqPath, readTestfile(t, testfile)),
 	}
 }
+
+func listResponses(t testingT, args createPlanArgs) []response {
+	reqPath := fmt.Sprintf("/repos/%s/%s/releases", args.owner, args.repo)
+	testfile := fmt.Sprintf("GET-%s-%s-releases.json", args.owner, args.repo)
+	return []response{
+		get(reqPath, readTestfile(t, testfile)),
+	}
+}
+
+func TestCreatePlanAssetURL(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		site    config.Site
+		api     string
+		browser string
+		want    string
+	}{{
+		name: "enterprise with token",
+		site: config.Site{
+			Type:    config.TypeGitHub,
+			Address: "ghe.example.com",
+			Auth:    &config.Auth{Token: "secret"},
+		},
+		api:     "https://ghe.example.com/api/v3/repos/corp/tool/releases/assets/7",
+		browser: "https://ghe.example.com/corp/tool/releases/download/v1.0.0/tool-linux-amd64",
+		want:    "https://ghe.example.com/api/v3/repos/corp/tool/releases/assets/7",
+	}, {
+		name: "github.com with token",
+		site: config.Site{
+			Type: config.TypeGitHub,
+			Auth: &config.Auth{Token: "secret"},
+		},
+		api:     "https://api.github.com/repos/corp/tool/releases/assets/7",
+		browser: "https://github.com/corp/tool/releases/download/v1.0.0/tool-linux-amd64",
+		want:    "https://api.github.com/repos/corp/tool/releases/assets/7",
+	}, {
+		name:    "github.com without token",
+		site:    config.Site{Type: config.TypeGitHub},
+		api:     "https://api.github.com/repos/corp/tool/releases/assets/7",
+		browser: "https://github.com/corp/tool/releases/download/v1.0.0/tool-linux-amd64",
+		want:    "https://github.com/corp/tool/releases/download/v1.0.0/tool-linux-amd64",
+	}}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ghapi.WithTestClient(t, func(client *gh.Client, mux *http.ServeMux) {
+				ctx := output.WithContext(context.TestContext(t), output.NewTestPrinter())
+				ctx = ghapi.WithContext(ctx, client)
+				mux.HandleFunc("/repos/corp/tool/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = fmt.Fprintf(w, `{"tag_name": "v1.0.0", "assets": [{
+						"id": 7, "name": "tool-linux-amd64", "size": 3,
+						"url": %q, "browser_download_url": %q
+					}]}`, tc.api, tc.browser)
+				})
+				args := download.Args{Args: install.Args{
+					Asset: github.Asset{
+						FileName:        github.FileName{BaseName: "tool"},
+						Architecture:    github.ArchAMD64,
+						OperatingSystem: github.OSLinuxGnu,
+						Release: github.Release{
+							Tag:        github.LatestTag,
+							Repository: artifact.Repository{Owner: "corp", Repo: "tool"},
+						},
+					},
+					Site: tc.site,
+				}}
+				p, err := download.CreatePlan(ctx, args)
+				require.NoError(t, err)
+				require.Len(t, p.Assets, 1)
+				assert.Equal(t, tc.want, p.Assets[0].URL)
+			})
+		})
+	}
+}
+
+func TestCreatePlanPicksBestScoredAsset(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "example", Repo: "tool"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v1.0.0",
+		Assets: map[string][]byte{
+			"tool-linux-amd64.zip":         nil,
+			"tool-linux-amd64.tar.gz":      nil,
+			"tool-linux-amd64.tar.gz.sig":  nil,
+			"tool-linux-amd64-debug.zip":   nil,
+			"tool-linux-amd64.tar.gz.pem":  nil,
+			"tool-darwin-arm64.tar.gz":     nil,
+			"tool-linux-amd64.sbom.tar.gz": nil,
+		},
+	}))
+	args := download.Args{
+		Args: install.Args{
+			Asset: github.Asset{
+				FileName:        github.FileName{BaseName: "tool"},
+				Architecture:    github.ArchAMD64,
+				OperatingSystem: github.OSLinuxGnu,
+				Release:         github.Release{Tag: github.LatestTag, Repository: repo},
+			},
+		},
+	}
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	require.Len(t, plan.Assets, 1)
+	assert.Equal(t, "tool-linux-amd64.tar.gz", plan.Assets[0].Name)
+}
+
+func TestCreatePlanWithRule(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	ctx = config.WithContext(ctx, config.Config{Rules: []config.Rule{{
+		Repository: "example/tool",
+		AssetRegex: `^toolkit-\w+-\w+\.tgz$`,
+		Aliases:    map[string]string{"aarch64": "arm64", "macos": "darwin"},
+		Checksums:  "SHASUMS",
+	}}})
+	repo := artifact.Repository{Owner: "example", Repo: "tool"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v1.0.0",
+		Assets: map[string][]byte{
+			"toolkit-macos-aarch64.tgz": nil,
+			"toolkit-linux-x86_64.tgz":  nil,
+			"tool-macos-aarch64.tgz":    nil,
+			"SHASUMS":                   nil,
+		},
+	}))
+	args := download.Args{
+		Args: install.Args{
+			Asset: github.Asset{
+				FileName:        github.FileName{BaseName: "tool"},
+				Architecture:    github.ArchARM64,
+				OperatingSystem: github.OSDarwin,
+				Release:         github.Release{Tag: github.LatestTag, Repository: repo},
+			},
+		},
+	}
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	names := make([]string, 0, len(plan.Assets))
+	for _, a := range plan.Assets {
+		names = append(names, a.Name)
+	}
+	assert.Equal(t, []string{"toolkit-macos-aarch64.tgz", "SHASUMS"}, names)
+}
+
+func TestCreatePlanWithArchiveName(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "example", Repo: "tool"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v1.0.0",
+		Assets: map[string][]byte{
+			"tool_1.0.0_universal.tgz": nil,
+			"tool-linux-amd64.tar.gz":  nil,
+			"checksums.txt":            nil,
+		},
+	}))
+	args := download.Args{Args: install.Parse("example/tool::tool_*_universal.tgz")}
+	args.Architecture = github.ArchAMD64
+	args.OperatingSystem = github.OSLinuxGnu
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	names := make([]string, 0, len(plan.Assets))
+	for _, a := range plan.Assets {
+		names = append(names, a.Name)
+	}
+	assert.ElementsMatch(t, []string{"tool_1.0.0_universal.tgz", "checksums.txt"}, names)
+}
+
+func TestCreatePlanWarnsOfRequestedPreRelease(t *testing.T) {
+	t.Parallel()
+	printer := output.NewTestPrinter()
+	ctx := output.WithContext(context.TestContext(t), printer)
+	repo := artifact.Repository{Owner: "example", Repo: "tool"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag:    "v1.0.0",
+		Assets: map[string][]byte{"tool-linux-amd64": nil},
+	}, fake.Release{
+		Tag:        "v1.1.0-rc.1",
+		PreRelease: true,
+		Assets:     map[string][]byte{"tool-linux-amd64": nil},
+	}))
+	args := download.Args{Args: install.Parse("example/tool@v1.1.0-rc.1")}
+	args.Architecture = github.ArchAMD64
+	args.OperatingSystem = github.OSLinuxGnu
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0-rc.1", plan.Tag)
+	assert.Contains(t, printer.Outputs().Out.String(), "is a pre-release")
+}
+
+func TestCreatePlanLatestPreReleaseWithoutVersions(t *testing.T) {
+	t.Parallel()
+	ctx := output.WithContext(context.TestContext(t), output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "example", Repo: "tool"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag:    "nightly",
+		Assets: map[string][]byte{"tool-linux-amd64": nil},
+	}, fake.Release{
+		Tag:        "edge",
+		PreRelease: true,
+		Assets:     map[string][]byte{"tool-linux-amd64": nil},
+	}))
+	args := download.Args{Args: install.Parse("example/tool")}
+	args.Architecture = github.ArchAMD64
+	args.OperatingSystem = github.OSLinuxGnu
+	args.PreRelease = true
+
+	_, err := download.CreatePlan(ctx, args)
+	require.ErrorIs(t, err, download.ErrNoVersionedRelease)
+	assert.NotErrorIs(t, err, download.ErrNoMatchingRelease)
+	assert.NotContains(t, err.Error(), "version constraint")
+}
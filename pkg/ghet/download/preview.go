@@ -0,0 +1,98 @@
+package download
+
+import (
+	"path"
+
+	"github.com/cardil/ghet/pkg/artifact"
+)
+
+// AssetCategory tells what the asset is used for.
+type AssetCategory string
+
+const (
+	// CategoryArchive is an archive the binaries are extracted from.
+	CategoryArchive AssetCategory = "archive"
+	// CategoryBinary is a binary used as is.
+	CategoryBinary AssetCategory = "binary"
+	// CategoryChecksum is a file with the checksums of the other assets.
+	CategoryChecksum AssetCategory = "checksum"
+)
+
+// Preview describes what the plan would download, and where the binaries
+// would be placed.
+type Preview struct {
+	Repository artifact.Repository `json:"repository"`
+	Tag        string              `json:"tag"`
+	Assets     []PreviewAsset      `json:"assets"`
+	// Checksum is the name of the checksum file the assets would be verified
+	// against. It's empty if there are no checksums, or there are more of
+	// them, and one would be chosen interactively.
+	Checksum string `json:"checksum,omitempty"`
+	// Targets are the paths the binaries would be placed at. With multiple
+	// binaries extracted from an archive, their names aren't known upfront,
+	// so the destination directory is given instead.
+	Targets []string `json:"targets"`
+}
+
+// PreviewAsset is an asset of the plan, with its category.
+type PreviewAsset struct {
+	artifact.Asset
+	Category AssetCategory `json:"category"`
+}
+
+// Preview describes the plan without downloading anything.
+func (p Plan) Preview(args Args) Preview {
+	index := p.index()
+	assets := make([]PreviewAsset, 0, len(p.Assets))
+	for _, a := range index.Archives {
+		assets = append(assets, PreviewAsset{Asset: a, Category: CategoryArchive})
+	}
+	for _, a := range index.Binaries {
+		assets = append(assets, PreviewAsset{Asset: a, Category: CategoryBinary})
+	}
+	for _, a := range index.Checksums {
+		assets = append(assets, PreviewAsset{Asset: a, Category: CategoryChecksum})
+	}
+	checksum := ""
+	if len(index.Checksums) == 1 {
+		checksum = index.Checksums[0].Name
+	}
+	return Preview{
+		Repository: args.Repository,
+		Tag:        p.Tag,
+		Assets:     assets,
+		Checksum:   checksum,
+		Targets:    p.targets(args, index),
+	}
+}
+
+// targets mirrors the paths the binaries are extracted and moved to.
+func (p Plan) targets(args Args, index artifact.IndexedAssets) []string {
+	targets := make([]string, 0, len(index.Archives)+len(index.Binaries))
+	if len(index.Archives) > 0 {
+		if args.MultipleBinaries {
+			targets = append(targets, args.Destination)
+		} else {
+			targets = append(targets, path.Join(args.Destination, args.ToString()))
+		}
+	}
+	binaryName := args.ToString()
+	for _, binary := range index.Binaries {
+		if len(index.Binaries) > 1 {
+			binaryName = binary.Name
+		}
+		targets = append(targets, path.Join(args.Destination, binaryName))
+	}
+	return targets
+}
+
+func categoryOf(asset artifact.Asset, checksums []string) AssetCategory {
+	index := artifact.CreateIndex([]artifact.Asset{asset}, checksums...)
+	switch {
+	case len(index.Archives) > 0:
+		return CategoryArchive
+	case len(index.Checksums) > 0:
+		return CategoryChecksum
+	}
+	return CategoryBinary
+}
@@ -0,0 +1,59 @@
+package download_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+)
+
+func TestPlanPreview(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	repo := artifact.Repository{Owner: "derailed", Repo: "k9s"}
+	ctx = provider.WithContext(ctx, fake.New().Add(repo, fake.Release{
+		Tag: "v0.32.5",
+		Assets: map[string][]byte{
+			"k9s_Linux_amd64.tar.gz":  []byte("archive"),
+			"k9s_Darwin_arm64.tar.gz": []byte("darwin"),
+			"checksums.txt":           []byte("sums"),
+		},
+	}))
+	args := download.Args{
+		Args: install.Args{
+			Asset: pkggithub.Asset{
+				FileName:        pkggithub.FileName{BaseName: "k9s"},
+				Architecture:    pkggithub.ArchAMD64,
+				OperatingSystem: pkggithub.OSLinuxGnu,
+				Release:         pkggithub.Release{Tag: pkggithub.LatestTag, Repository: repo},
+			},
+		},
+		Destination: "/opt/bin",
+	}
+
+	plan, err := download.CreatePlan(ctx, args)
+	require.NoError(t, err)
+	pv := plan.Preview(args)
+
+	assert.Equal(t, repo, pv.Repository)
+	assert.Equal(t, "v0.32.5", pv.Tag)
+	categories := make(map[string]download.AssetCategory, len(pv.Assets))
+	for _, a := range pv.Assets {
+		categories[a.Name] = a.Category
+	}
+	assert.Equal(t, map[string]download.AssetCategory{
+		"k9s_Linux_amd64.tar.gz": download.CategoryArchive,
+		"checksums.txt":          download.CategoryChecksum,
+	}, categories)
+	assert.Equal(t, "checksums.txt", pv.Checksum)
+	assert.Equal(t, []string{"/opt/bin/k9s"}, pv.Targets)
+}
@@ -0,0 +1,122 @@
+package download
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+const (
+	partialSuffix = ".part"
+	metaSuffix    = ".part.json"
+	cacheFileMode = 0o644
+)
+
+// partial is a partially downloaded asset. It's resumed only if the server
+// gave a validator, so a changed asset isn't stitched together with the old
+// partial content.
+type partial struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	cachePath string
+	size      int64
+}
+
+func loadPartial(cachePath string) partial {
+	p := partial{cachePath: cachePath}
+	bytes, err := os.ReadFile(p.metaPath())
+	if err != nil {
+		return p
+	}
+	if err = json.Unmarshal(bytes, &p); err != nil {
+		return partial{cachePath: cachePath}
+	}
+	if fi, serr := os.Stat(p.path()); serr == nil {
+		p.size = fi.Size()
+	}
+	return p
+}
+
+func (p partial) path() string {
+	return p.cachePath + partialSuffix
+}
+
+func (p partial) metaPath() string {
+	return p.cachePath + metaSuffix
+}
+
+// validator returns the value for the If-Range header.
+func (p partial) validator() string {
+	if p.ETag != "" {
+		return p.ETag
+	}
+	return p.LastModified
+}
+
+func (p partial) resumable(size int) bool {
+	return p.size > 0 && p.size < int64(size) && p.validator() != ""
+}
+
+// header asks for the rest of the asset, if the partial is resumable.
+func (p partial) header(size int) http.Header {
+	h := http.Header{}
+	if !p.resumable(size) {
+		return h
+	}
+	h.Set("Range", fmt.Sprintf("bytes=%d-", p.size))
+	h.Set("If-Range", p.validator())
+	return h
+}
+
+// resumes checks the response continues right where the partial ends.
+func (p partial) resumes(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+	var start, end, total int64
+	_, err := fmt.Sscanf(resp.Header.Get("Content-Range"),
+		"bytes %d-%d/%d", &start, &end, &total)
+	return err == nil && start == p.size
+}
+
+// restart starts the partial over, remembering the validators of the
+// response.
+func (p *partial) restart(resp *http.Response) error {
+	p.ETag = resp.Header.Get("ETag")
+	p.LastModified = resp.Header.Get("Last-Modified")
+	p.size = 0
+	bytes, err := json.Marshal(p)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(p.metaPath(), bytes, cacheFileMode))
+}
+
+// open opens the partial file for writing, either appending to it, or
+// truncating it.
+func (p partial) open(appending bool) (*os.File, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appending {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(p.path(), flags, cacheFileMode)
+	return f, errors.WithStack(err)
+}
+
+// complete moves the fully downloaded partial in place of the cached asset.
+func (p partial) complete() error {
+	if err := os.Rename(p.path(), p.cachePath); err != nil {
+		return errors.WithStack(err)
+	}
+	_ = os.Remove(p.metaPath())
+	return nil
+}
+
+func (p partial) discard() {
+	_ = os.Remove(p.path())
+	_ = os.Remove(p.metaPath())
+}
@@ -0,0 +1,151 @@
+//go:build !race
+
+package download_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+)
+
+const resumedAsset = "agg-x86_64-unknown-linux-gnu"
+
+func TestResumeDownload(t *testing.T) {
+	t.Parallel()
+	tcs := []resumeTestCase{{
+		name: "resumes",
+	}, {
+		name:    "asset changed",
+		changed: true,
+	}, {
+		name:         "ranges ignored",
+		ignoreRanges: true,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc resumeTestCase) run(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	ctx = configdir.WithCacheDir(ctx, t.TempDir())
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	original, err := fs.ReadFile("testdata/" + resumedAsset)
+	require.NoError(t, err)
+	srv := &flakyServer{content: original, etag: `"v1"`}
+
+	ghapi.WithTestClient(t, func(client *github.Client, mux *http.ServeMux) {
+		ctx = ghapi.WithContext(ctx, client)
+		mux.Handle("/"+resumedAsset, srv)
+		wd := t.TempDir()
+		plan := download.Plan{Assets: []artifact.Asset{{
+			ID:          1,
+			Name:        resumedAsset,
+			ContentType: "application/octet-stream",
+			Size:        len(original),
+			URL:         client.BaseURL.String() + resumedAsset,
+		}}}
+		args := download.Args{
+			Args: install.Args{
+				Asset: pkggithub.Asset{
+					FileName:        pkggithub.FileName{BaseName: "agg"},
+					Architecture:    pkggithub.ArchAMD64,
+					OperatingSystem: pkggithub.OSLinuxGnu,
+				},
+			},
+			Destination: wd,
+		}
+
+		_, err = plan.Download(ctx, args)
+		require.Error(t, err)
+		assert.NoFileExists(t, path.Join(wd, "agg"))
+
+		want := original
+		if tc.changed {
+			want = bytes.ToUpper(original)
+			srv.update(want, `"v2"`)
+		}
+		srv.setIgnoreRanges(tc.ignoreRanges)
+		_, err = plan.Download(ctx, args)
+		require.NoError(t, err)
+
+		got, rerr := os.ReadFile(path.Join(wd, "agg"))
+		require.NoError(t, rerr)
+		assert.Equal(t, want, got)
+		assert.Equal(t, "bytes="+strconv.Itoa(len(original)/2)+"-", srv.lastRange())
+	})
+}
+
+// flakyServer breaks the first download in half, and serves the following
+// ones, supporting the range requests unless told to ignore them.
+type flakyServer struct {
+	mu           sync.Mutex
+	content      []byte
+	etag         string
+	ignoreRanges bool
+	requests     int
+	ranges       []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	first, content, etag, ignore := s.requests == 1, s.content, s.etag, s.ignoreRanges
+	s.mu.Unlock()
+	w.Header().Set("ETag", etag)
+	if first {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write(content[:len(content)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	if ignore {
+		_, _ = w.Write(content)
+		return
+	}
+	http.ServeContent(w, r, resumedAsset, time.Time{}, bytes.NewReader(content))
+}
+
+func (s *flakyServer) update(content []byte, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.content, s.etag = content, etag
+}
+
+func (s *flakyServer) setIgnoreRanges(ignore bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignoreRanges = ignore
+}
+
+func (s *flakyServer) lastRange() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ranges[len(s.ranges)-1]
+}
+
+type resumeTestCase struct {
+	name         string
+	changed      bool
+	ignoreRanges bool
+}
@@ -0,0 +1,105 @@
+//go:build !race
+
+package download_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+)
+
+func TestDownloadFromURLTemplate(t *testing.T) {
+	t.Parallel()
+	binary := []byte("#!/bin/sh\necho kubectl\n")
+	sum := sha256.Sum256(binary)
+	for name, checksum := range map[string]string{
+		"matching":   hex.EncodeToString(sum[:]),
+		"mismatched": hex.EncodeToString(make([]byte, sha256.Size)),
+	} {
+		checksum := checksum
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tmpDir := t.TempDir()
+			ctx := context.TestContext(t)
+			ctx = configdir.WithCacheDir(ctx, tmpDir)
+			ctx = configdir.WithConfigDir(ctx, tmpDir)
+			ctx = output.WithContext(ctx, output.NewTestPrinter())
+			ghapi.WithTestClient(t, func(client *github.Client, mux *http.ServeMux) {
+				ctx = ghapi.WithContext(ctx, client)
+				mux.HandleFunc("/repos/kubernetes/kubernetes/tags",
+					func(w http.ResponseWriter, _ *http.Request) {
+						_, _ = w.Write([]byte(`[{"name": "v1.30.2"}, {"name": "v1.29.6"}]`))
+					})
+				const bin = "/release/v1.30.2/bin/linux/amd64/kubectl"
+				var gets, heads atomic.Int32
+				mux.HandleFunc(bin, func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodHead {
+						heads.Add(1)
+					} else {
+						gets.Add(1)
+					}
+					_, _ = w.Write(binary)
+				})
+				mux.HandleFunc(bin+".sha256", func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte(checksum))
+				})
+				base := client.BaseURL.String() + "release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/{{.Name}}{{.Ext}}"
+				wd := t.TempDir()
+				args := download.Args{
+					Args: install.Args{
+						Asset: pkggithub.Asset{
+							FileName:        pkggithub.FileName{BaseName: "kubectl"},
+							Architecture:    pkggithub.ArchAMD64,
+							OperatingSystem: pkggithub.OSLinuxGnu,
+							Release: pkggithub.Release{
+								Tag: pkggithub.LatestTag,
+								Repository: artifact.Repository{
+									Owner: "kubernetes", Repo: "kubernetes",
+								},
+							},
+						},
+						Site: config.Site{
+							Type:     config.TypeURL,
+							Address:  "dl.k8s.io",
+							Template: &config.Template{URL: base, ChecksumURL: base + ".sha256"},
+						},
+					},
+					Destination: wd,
+				}
+
+				plan, err := download.CreatePlan(ctx, args)
+				require.NoError(t, err)
+				assert.Equal(t, "v1.30.2", plan.Tag)
+				res, err := plan.Download(ctx, args)
+				if name == "mismatched" {
+					assert.ErrorIs(t, err, download.ErrChecksumMismatch)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, download.ChecksumsVerified, res.Verification.Checksums)
+				assert.Equal(t, int32(1), heads.Load(), "the size is asked for")
+				assert.Equal(t, int32(1), gets.Load(), "the binary is downloaded once")
+				fi, err := os.Stat(path.Join(wd, "kubectl"))
+				require.NoError(t, err)
+				assert.True(t, isExecutable(fi.Mode()))
+			})
+		})
+	}
+}
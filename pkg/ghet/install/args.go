@@ -13,6 +13,9 @@ type Args struct {
 	config.Site
 	MultipleBinaries bool
 	VerifyInArchive  bool
+	// PreRelease allows pre-releases to be picked when resolving the latest
+	// release or a version constraint.
+	PreRelease bool
 }
 
 func (a Args) WithDefaults() Args {
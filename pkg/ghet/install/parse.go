@@ -3,6 +3,7 @@ package install
 import (
 	"strings"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/cardil/ghet/pkg/config"
 	"github.com/cardil/ghet/pkg/github"
 )
@@ -10,28 +11,31 @@ import (
 // Parse parses the installation arguments from the given spec.
 //
 //	The spec is a string that contains the arguments in a format of
-//	"owner/repo[@version][::archive-name][!!binary-name]".
+//	"owner/repo[@version][::archive-name][!!binary-name]". The version can
+//	be an exact tag, "latest", or a constraint like "^2.40" or ">=1.0,<2".
+//	The archive name, which can be a glob like "tool_*_linux.tgz", picks the
+//	release asset in place of the matching by the platform.
 func Parse(spec string) Args {
 	const expectedParts = 2
-	parts := strings.SplitN(spec, "@", expectedParts)
+	binary := ""
+	parts := strings.SplitN(spec, "!!", expectedParts)
+	if len(parts) >= expectedParts {
+		binary = parts[1]
+	}
+	parts = strings.SplitN(parts[0], "::", expectedParts)
+	archive := ""
+	if len(parts) >= expectedParts {
+		archive = parts[1]
+	}
+	parts = strings.SplitN(parts[0], "@", expectedParts)
 	p2 := strings.SplitN(parts[0], "/", expectedParts)
 	owner, repo := "", ""
 	if len(p2) > 1 {
 		owner, repo = p2[0], p2[1]
 	}
-	version := "latest"
-	archive := ""
-	binary := ""
-	if len(parts) >= expectedParts {
-		parts = strings.SplitN(parts[1], "::", expectedParts)
-		version = parts[0]
-		if len(parts) >= expectedParts {
-			archive = parts[1]
-		}
-	}
-	parts = strings.SplitN(archive, "!!", expectedParts)
-	if len(parts) >= expectedParts {
-		_, binary = parts[0], parts[1]
+	version := github.LatestTag
+	if len(parts) >= expectedParts && parts[1] != "" {
+		version = parts[1]
 	}
 	if binary == "" {
 		binary = repo
@@ -47,9 +51,10 @@ func Parse(spec string) Args {
 				BaseName:  binary,
 				Extension: ext,
 			},
+			Archive: archive,
 			Release: github.Release{
 				Tag: version,
-				Repository: github.Repository{
+				Repository: artifact.Repository{
 					Owner: owner,
 					Repo:  repo,
 				},
@@ -3,6 +3,7 @@ package install_test
 import (
 	"testing"
 
+	"github.com/cardil/ghet/pkg/artifact"
 	"github.com/cardil/ghet/pkg/ghet/install"
 	"github.com/cardil/ghet/pkg/github"
 )
@@ -19,7 +20,7 @@ func TestParse(t *testing.T) {
 				},
 				Release: github.Release{
 					Tag: "latest",
-					Repository: github.Repository{
+					Repository: artifact.Repository{
 						Owner: "cardil",
 						Repo:  "ghet",
 					},
@@ -34,15 +35,65 @@ func TestParse(t *testing.T) {
 					BaseName:  "binary-name",
 					Extension: "",
 				},
+				Archive: "archive-name",
 				Release: github.Release{
 					Tag: "version",
-					Repository: github.Repository{
+					Repository: artifact.Repository{
 						Owner: "owner",
 						Repo:  "repo",
 					},
 				},
 			},
 		},
+	}, {
+		args: "owner/repo!!binary-name",
+		want: install.Args{
+			Asset: github.Asset{
+				FileName: github.FileName{
+					BaseName: "binary-name",
+				},
+				Release: github.Release{
+					Tag: "latest",
+					Repository: artifact.Repository{
+						Owner: "owner",
+						Repo:  "repo",
+					},
+				},
+			},
+		},
+	}, {
+		args: "owner/repo::archive-name",
+		want: install.Args{
+			Asset: github.Asset{
+				FileName: github.FileName{
+					BaseName: "repo",
+				},
+				Archive: "archive-name",
+				Release: github.Release{
+					Tag: "latest",
+					Repository: artifact.Repository{
+						Owner: "owner",
+						Repo:  "repo",
+					},
+				},
+			},
+		},
+	}, {
+		args: "cli/cli@>=2.40,<3!!gh",
+		want: install.Args{
+			Asset: github.Asset{
+				FileName: github.FileName{
+					BaseName: "gh",
+				},
+				Release: github.Release{
+					Tag: ">=2.40,<3",
+					Repository: artifact.Repository{
+						Owner: "cli",
+						Repo:  "cli",
+					},
+				},
+			},
+		},
 	}, {
 		args: "owner/repo@version",
 		want: install.Args{
@@ -52,7 +103,7 @@ func TestParse(t *testing.T) {
 				},
 				Release: github.Release{
 					Tag: "version",
-					Repository: github.Repository{
+					Repository: artifact.Repository{
 						Owner: "owner",
 						Repo:  "repo",
 					},
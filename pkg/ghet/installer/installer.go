@@ -0,0 +1,137 @@
+package installer
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+const replacedSuffix = ".ght-old"
+
+// Install downloads the release described by the arguments into its own
+// directory in the versions directory, makes it the active version by linking
+// its binaries into the destination, and records the installation.
+func Install(ctx context.Context, args download.Args) (*download.Result, error) {
+	return install(ctx, args, func(*state.Installation) {})
+}
+
+// Upgrade installs the given release of the installed tool, and makes it the
+// active version. The previously active version is removed. The installation
+// keeps the version it was installed with, so later upgrades honor it.
+func Upgrade(
+	ctx context.Context, inst state.Installation, args download.Args,
+) (*download.Result, error) {
+	args.Destination = binDirOf(ctx, inst)
+	res, err := install(ctx, args, func(next *state.Installation) {
+		next.Version = inst.Version
+		if next.Tag != inst.Tag {
+			next.DeleteVersion(inst.Tag)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Tag != inst.Tag {
+		removeVersionDir(ctx, inst.Repository, inst.Tag)
+	}
+	return res, nil
+}
+
+// VersionDir returns the directory the given version of the tool is kept in.
+func VersionDir(ctx context.Context, repo artifact.Repository, tag string) string {
+	return path.Join(RepoDir(ctx, repo), url.PathEscape(tag))
+}
+
+// RepoDir returns the directory all the versions of the tool are kept in.
+func RepoDir(ctx context.Context, repo artifact.Repository) string {
+	return path.Join(configdir.Versions(ctx), repo.Owner, repo.Repo)
+}
+
+func install(
+	ctx context.Context, args download.Args, modify func(inst *state.Installation),
+) (*download.Result, error) {
+	ctx = logging.EnsureLogger(ctx)
+	binDir := args.Destination
+	dir := RepoDir(ctx, args.Repository)
+	if err := os.MkdirAll(dir, executableMode); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	stage, err := os.MkdirTemp(dir, ".ght-install-*")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(stage)
+	}()
+	args.Destination = stage
+	res, err := download.Action(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	verDir := VersionDir(ctx, args.Repository, res.Tag)
+	if err = replaceDir(stage, verDir); err != nil {
+		return nil, err
+	}
+	for i, fp := range res.Binaries {
+		res.Binaries[i] = path.Join(verDir, strings.TrimPrefix(fp, stage))
+	}
+	args.Destination = binDir
+	inst, err := state.NewInstallation(args, res)
+	if err != nil {
+		return nil, err
+	}
+	if err = state.New(ctx).Update(ctx, func(db *state.Database) error {
+		prev, _ := db.Find(inst.Repository)
+		inst.Shims = prev.Shims
+		links, lerr := link(ctx, binDir, inst.Active().Binaries, prev.Links, inst.Shims)
+		if lerr != nil {
+			return lerr
+		}
+		inst.Links = links
+		db.Add(inst)
+		inst, _ = db.Find(inst.Repository)
+		modify(&inst)
+		db.Put(inst)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	res.Links = inst.Links
+	tui.NewWidgets(ctx).Printf("🔗 Activated %s of %s",
+		color.Cyan.Sprint(res.Tag), color.Cyan.Sprint(inst.Repository))
+	return res, nil
+}
+
+// replaceDir moves the source directory in place of the target one, which
+// could exist already, if the same version is installed again.
+func replaceDir(src, target string) error {
+	if _, err := os.Stat(target); err == nil {
+		old := target + replacedSuffix
+		_ = os.RemoveAll(old)
+		if err = os.Rename(target, old); err != nil {
+			return errors.WithStack(err)
+		}
+		defer func() {
+			_ = os.RemoveAll(old)
+		}()
+	}
+	return errors.WithStack(os.Rename(src, target))
+}
+
+func removeVersionDir(ctx context.Context, repo artifact.Repository, tag string) {
+	dir := VersionDir(ctx, repo, tag)
+	if err := os.RemoveAll(dir); err != nil {
+		logging.LoggerFrom(ctx).Warnf("Can't remove version %s: %v", dir, err)
+	}
+}
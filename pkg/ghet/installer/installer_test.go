@@ -0,0 +1,111 @@
+//go:build !race
+
+package installer_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	gh "github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestSideBySideVersions(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	ctx = configdir.WithCacheDir(ctx, t.TempDir())
+	ctx = configdir.WithVersionsDir(ctx, t.TempDir())
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	bindir := t.TempDir()
+	bin := path.Join(bindir, "kubectl")
+	repo := artifact.Repository{Owner: "kubernetes", Repo: "kubectl"}
+	tags := []string{"v1.28.4", "v1.30.0"}
+
+	ghapi.WithTestClient(t, func(client *gh.Client, mux *http.ServeMux) {
+		ctx = ghapi.WithContext(ctx, client)
+		configureMux(mux, client.BaseURL.String(), tags)
+
+		for _, tag := range tags {
+			args := install.Parse("kubernetes/kubectl@" + tag)
+			res, err := installer.Install(ctx, download.Args{
+				Args:        args,
+				Destination: bindir,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{bin}, res.Links)
+			assert.Equal(t, binaryOf(tag), readFile(t, bin))
+		}
+	})
+
+	db, err := state.New(ctx).Load(ctx)
+	require.NoError(t, err)
+	inst, ok := db.Find(repo)
+	require.True(t, ok)
+	assert.Equal(t, "v1.30.0", inst.Tag)
+	assert.Len(t, inst.Versions, len(tags))
+	assert.Equal(t, []string{bin}, inst.Links)
+
+	require.NoError(t, installer.Use(ctx, "kubectl", "v1.28.4"))
+	assert.Equal(t, binaryOf("v1.28.4"), readFile(t, bin))
+	db, err = state.New(ctx).Load(ctx)
+	require.NoError(t, err)
+	inst, _ = db.Find(repo)
+	assert.Equal(t, "v1.28.4", inst.Tag)
+
+	err = installer.Use(ctx, "kubernetes/kubectl", "v1.29.0")
+	require.ErrorIs(t, err, state.ErrNotInstalled)
+	assert.Equal(t, binaryOf("v1.28.4"), readFile(t, bin))
+
+	require.NoError(t, installer.Shim(ctx, nil, true))
+	assert.Contains(t, readFile(t, bin), "exec 'kubectl' \"$@\"")
+	require.NoError(t, installer.Use(ctx, "kubectl", "v1.30.0"))
+	assert.Contains(t, readFile(t, bin), "exec 'kubectl' \"$@\"")
+	require.NoError(t, installer.Shim(ctx, []string{"kubectl"}, false))
+	assert.Equal(t, binaryOf("v1.30.0"), readFile(t, bin))
+}
+
+func configureMux(mux *http.ServeMux, baseURL string, tags []string) {
+	asset := fmt.Sprintf("kubectl-%s-%s", github.CurrentOS(), github.CurrentArchitecture())
+	for _, tag := range tags {
+		tag := tag
+		mux.HandleFunc("/repos/kubernetes/kubectl/releases/tags/"+tag,
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"tag_name": %q, "assets": [
+					{"id": 1, "name": %q, "size": %d, "content_type": "application/octet-stream",
+					 "browser_download_url": "%sdownload/%s/%s"}
+				]}`, tag, asset, len(binaryOf(tag)), baseURL, tag, asset)
+			})
+		mux.HandleFunc("/download/"+tag+"/"+asset,
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(binaryOf(tag)))
+			})
+	}
+}
+
+func binaryOf(tag string) string {
+	return "#!/bin/sh\necho " + tag + "\n"
+}
+
+func readFile(t *testing.T, fp string) string {
+	t.Helper()
+	bytes, err := os.ReadFile(fp)
+	require.NoError(t, err)
+	return string(bytes)
+}
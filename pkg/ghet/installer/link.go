@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"knative.dev/client/pkg/output/logging"
+)
+
+const executableMode = 0o750
+
+// link makes the binaries available in the bin directory, by atomically
+// replacing the links, or shims, there. The previous links, that weren't
+// replaced, are removed.
+func link(
+	ctx context.Context, binDir string,
+	binaries []state.Binary, previous []string, shims bool,
+) ([]string, error) {
+	if err := os.MkdirAll(binDir, executableMode); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	links := make([]string, 0, len(binaries))
+	for _, bin := range binaries {
+		lp := path.Join(binDir, path.Base(bin.Path))
+		var err error
+		if shims {
+			lp, err = writeShim(lp)
+		} else {
+			err = symlink(bin.Path, lp)
+		}
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, lp)
+	}
+	current := make(map[string]bool, len(links))
+	for _, lp := range links {
+		current[lp] = true
+	}
+	stale := make([]string, 0, len(previous))
+	for _, lp := range previous {
+		if !current[lp] {
+			stale = append(stale, lp)
+		}
+	}
+	Unlink(ctx, stale)
+	return links, nil
+}
+
+// Unlink removes the given links, or shims. Other files are kept, as they
+// weren't placed there by ght.
+func Unlink(ctx context.Context, links []string) {
+	l := logging.LoggerFrom(ctx)
+	for _, lp := range links {
+		fi, err := os.Lstat(lp)
+		if err != nil {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink == 0 && !isShim(lp) {
+			l.Warnf("Keeping %s, as it isn't a link", lp)
+			continue
+		}
+		if err = os.Remove(lp); err != nil {
+			l.Warnf("Can't remove link %s: %v", lp, err)
+		}
+	}
+}
+
+// symlink atomically points the link to the target, replacing whatever was
+// there before.
+func symlink(target, lp string) error {
+	tmp := tempPath(lp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(tmp, lp); err != nil {
+		_ = os.Remove(tmp)
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func tempPath(lp string) string {
+	tmp := path.Join(path.Dir(lp), ".ght-link-"+path.Base(lp))
+	_ = os.Remove(tmp)
+	return tmp
+}
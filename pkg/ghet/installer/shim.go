@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+const (
+	shimHeader = "Generated by ght shim, do not edit."
+	shimMode   = 0o755
+)
+
+// Shim replaces the links of the given tools, or all the installed ones if
+// none are given, with shims. A shim runs the version pinned by the nearest
+// project manifest, falling back to the active version. If disabled, the shims
+// are replaced back with the links.
+func Shim(ctx context.Context, tools []string, enable bool) error {
+	ctx = logging.EnsureLogger(ctx)
+	widgets := tui.NewWidgets(ctx)
+	return state.New(ctx).Update(ctx, func(db *state.Database) error {
+		insts := db.Installations
+		if len(tools) > 0 {
+			var err error
+			if insts, err = db.Resolve(tools); err != nil {
+				return err
+			}
+		}
+		for _, inst := range insts {
+			links, err := link(ctx, binDirOf(ctx, inst),
+				inst.Active().Binaries, inst.Links, enable)
+			if err != nil {
+				return err
+			}
+			inst.Links = links
+			inst.Shims = enable
+			db.Put(inst)
+			kind := "links"
+			if enable {
+				kind = "shims"
+			}
+			widgets.Printf("🪄 Placed %s for %s: %s", kind,
+				color.Cyan.Sprint(inst.Repository),
+				color.Cyan.Sprint(strings.Join(inst.Links, ", ")))
+		}
+		return nil
+	})
+}
+
+// writeShim atomically writes a shim in place of the link, that executes the
+// binary of the same name, through ght. It returns the path of the shim,
+// which differs from the link on the systems running the shims as scripts of
+// their own kind.
+func writeShim(lp string) (string, error) {
+	ght, err := os.Executable()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	sp := shimPath(lp)
+	script := shimScript(ght, path.Base(lp))
+	tmp := tempPath(sp)
+	if err = os.WriteFile(tmp, []byte(script), shimMode); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if err = os.Rename(tmp, sp); err != nil {
+		_ = os.Remove(tmp)
+		return "", errors.WithStack(err)
+	}
+	return sp, nil
+}
+
+func isShim(fp string) bool {
+	f, err := os.Open(fp)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for i := 0; i < 2 && sc.Scan(); i++ {
+		if strings.HasSuffix(sc.Text(), shimHeader) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package installer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shimPath returns the path of the shim, which is the link itself.
+func shimPath(lp string) string {
+	return lp
+}
+
+// shimScript returns a shell script, that replaces itself with ght.
+func shimScript(ght, binary string) string {
+	return fmt.Sprintf("#!/bin/sh\n# %s\nexec '%s' exec '%s' \"$@\"\n",
+		shimHeader, quote(ght), quote(binary))
+}
+
+// quote escapes the single quotes for the shell.
+func quote(s string) string {
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
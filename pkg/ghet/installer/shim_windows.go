@@ -0,0 +1,27 @@
+//go:build windows
+
+package installer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// shimPath returns the path of the batch file, which cmd and PowerShell find
+// in place of the binary, as ".cmd" is one of the PATHEXT extensions.
+func shimPath(lp string) string {
+	return strings.TrimSuffix(lp, path.Ext(lp)) + ".cmd"
+}
+
+// shimScript returns a batch file, that runs ght, and exits with its exit
+// code.
+func shimScript(ght, binary string) string {
+	return fmt.Sprintf("@echo off\r\nrem %s\r\n\"%s\" exec \"%s\" %%*\r\nexit /b %%ERRORLEVEL%%\r\n",
+		shimHeader, escape(ght), escape(binary))
+}
+
+// escape doubles the percent signs, which the batch files would expand.
+func escape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
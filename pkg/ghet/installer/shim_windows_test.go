@@ -0,0 +1,29 @@
+//go:build windows
+
+package installer
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteShim(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	sp, err := writeShim(path.Join(dir, "kubectl.exe"))
+	require.NoError(t, err)
+
+	assert.Equal(t, path.Join(dir, "kubectl.cmd"), sp)
+	bytes, err := os.ReadFile(sp)
+	require.NoError(t, err)
+	script := string(bytes)
+	assert.True(t, strings.HasPrefix(script, "@echo off\r\n"), script)
+	assert.Contains(t, script, `exec "kubectl.exe" %*`)
+	assert.True(t, isShim(sp))
+}
@@ -0,0 +1,53 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"emperror.dev/errors"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+// Use makes the given installed version of the tool the active one. The tool
+// can be given either as owner/repo or as a name of one of its binaries.
+func Use(ctx context.Context, tool, tag string) error {
+	ctx = logging.EnsureLogger(ctx)
+	var inst state.Installation
+	if err := state.New(ctx).Update(ctx, func(db *state.Database) error {
+		insts, err := db.Resolve([]string{tool})
+		if err != nil {
+			return err
+		}
+		inst = insts[0]
+		v, ok := inst.FindVersion(tag)
+		if !ok {
+			return errors.WithStack(fmt.Errorf("%w: %s@%s",
+				state.ErrNotInstalled, inst.Repository, tag))
+		}
+		links, err := link(ctx, binDirOf(ctx, inst), v.Binaries, inst.Links, inst.Shims)
+		if err != nil {
+			return err
+		}
+		inst.Tag = v.Tag
+		inst.Links = links
+		db.Put(inst)
+		return nil
+	}); err != nil {
+		return err
+	}
+	tui.NewWidgets(ctx).Printf("🔗 Activated %s of %s",
+		color.Cyan.Sprint(tag), color.Cyan.Sprint(inst.Repository))
+	return nil
+}
+
+func binDirOf(ctx context.Context, inst state.Installation) string {
+	if len(inst.Links) > 0 {
+		return path.Dir(inst.Links[0])
+	}
+	return configdir.Bin(ctx)
+}
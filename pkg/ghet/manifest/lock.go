@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/github"
+	"sigs.k8s.io/yaml"
+)
+
+// LockFileName is the name of the lockfile, pinning the resolved releases of
+// the tools listed in the project manifest.
+const LockFileName = ".ghet.lock"
+
+// ErrInvalidLock is returned when the lockfile can't be read.
+var ErrInvalidLock = errors.New("invalid lockfile")
+
+const lockFileMode = 0o644
+
+// Lock pins the tools of the project manifest to exact releases and assets.
+type Lock struct {
+	Tools []LockedTool `json:"tools"`
+}
+
+// LockedTool is a tool pinned to an exact release. The assets are kept per
+// platform, in a form of "<os>/<arch>", as each platform downloads different
+// ones.
+type LockedTool struct {
+	Spec      string                   `json:"spec"`
+	Tag       string                   `json:"tag"`
+	Platforms map[string][]LockedAsset `json:"platforms"`
+}
+
+// LockedAsset is a release asset along with its SHA-256 digest.
+type LockedAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// CurrentPlatform returns the platform key of the running system.
+func CurrentPlatform() string {
+	return fmt.Sprintf("%s/%s", github.CurrentOS(), github.CurrentArchitecture())
+}
+
+// LoadLock reads the lockfile from the given directory. A missing lockfile
+// is treated as an empty one.
+func LoadLock(dir string) (*Lock, error) {
+	fp := path.Join(dir, LockFileName)
+	bytes, err := os.ReadFile(fp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Lock{}, nil
+		}
+		return nil, asInvalid(ErrInvalidLock, fp, err)
+	}
+	l := &Lock{}
+	if err = yaml.UnmarshalStrict(bytes, l); err != nil {
+		return nil, asInvalid(ErrInvalidLock, fp, err)
+	}
+	return l, nil
+}
+
+// Find returns the locked tool for the given manifest spec.
+func (l *Lock) Find(spec string) (LockedTool, bool) {
+	for _, t := range l.Tools {
+		if t.Spec == spec {
+			return t, true
+		}
+	}
+	return LockedTool{}, false
+}
+
+// Digests returns the locked digests of the assets for the given platform,
+// keyed by the asset name.
+func (t LockedTool) Digests(platform string) (map[string]string, bool) {
+	assets, ok := t.Platforms[platform]
+	if !ok {
+		return nil, false
+	}
+	digests := make(map[string]string, len(assets))
+	for _, a := range assets {
+		digests[a.Name] = a.SHA256
+	}
+	return digests, true
+}
+
+// Save writes the lockfile to the given directory.
+func (l *Lock) Save(dir string) error {
+	bytes, err := yaml.Marshal(l)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+LockFileName+"-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = tmp.Write(bytes); err != nil {
+		_ = tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err = tmp.Chmod(lockFileMode); err != nil {
+		_ = tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp.Name(), path.Join(dir, LockFileName)))
+}
@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"emperror.dev/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// FileName is the name of the project manifest, listing the required tools.
+const FileName = ".ghet.yaml"
+
+// ErrInvalidManifest is returned when the project manifest can't be read.
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Manifest lists the tools required by a project. Each tool is given in the
+// same form as accepted by the install command, for example
+// "cli/cli@^2.40!!gh".
+type Manifest struct {
+	Tools []string `json:"tools"`
+}
+
+// Load reads the project manifest from the given directory.
+func Load(dir string) (*Manifest, error) {
+	fp := path.Join(dir, FileName)
+	bytes, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, asInvalid(ErrInvalidManifest, fp, err)
+	}
+	m := &Manifest{}
+	if err = yaml.UnmarshalStrict(bytes, m); err != nil {
+		return nil, asInvalid(ErrInvalidManifest, fp, err)
+	}
+	return m, nil
+}
+
+// FindProject walks up from the given directory, and returns the nearest
+// directory holding the project manifest or the lockfile.
+func FindProject(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, name := range []string{LockFileName, FileName} {
+			if _, err = os.Stat(path.Join(dir, name)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func asInvalid(sentinel error, fp string, err error) error {
+	return errors.WithStack(fmt.Errorf("%w: %s: %v", sentinel, fp, err))
+}
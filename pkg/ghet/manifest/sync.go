@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+// ErrOutdatedLock is returned in frozen mode, when the lockfile doesn't
+// match the project manifest.
+var ErrOutdatedLock = errors.New("lockfile is out of date")
+
+// Args are the arguments of the sync action.
+type Args struct {
+	// Dir is the project directory, holding the manifest and the lockfile.
+	Dir string
+	// BinDir is the directory to install the binaries to.
+	BinDir string
+	// Frozen fails the sync instead of updating the lockfile.
+	Frozen bool
+	// Parallel limits the number of assets downloaded at once.
+	Parallel int
+}
+
+// Action installs the tools listed in the project manifest. The tools
+// already pinned in the lockfile are installed exactly as locked, and their
+// downloaded assets must match the locked digests. The others are resolved
+// and pinned in the lockfile, unless in frozen mode.
+func Action(ctx context.Context, args Args) error {
+	ctx = logging.EnsureLogger(ctx)
+	m, err := Load(args.Dir)
+	if err != nil {
+		return err
+	}
+	lock, err := LoadLock(args.Dir)
+	if err != nil {
+		return err
+	}
+	if args.Frozen {
+		if err = verifyFrozen(m, lock); err != nil {
+			return err
+		}
+	}
+	tui.NewWidgets(ctx).Printf("🔄 Syncing %s tools from %s",
+		color.Cyan.Sprint(len(m.Tools)), color.Cyan.Sprint(FileName))
+	platform := CurrentPlatform()
+	synced := &Lock{Tools: make([]LockedTool, 0, len(m.Tools))}
+	for _, spec := range m.Tools {
+		var tool LockedTool
+		if tool, err = syncTool(ctx, args, spec, lock, platform); err != nil {
+			return err
+		}
+		synced.Tools = append(synced.Tools, tool)
+	}
+	if args.Frozen {
+		return nil
+	}
+	return synced.Save(args.Dir)
+}
+
+func syncTool(
+	ctx context.Context, args Args, spec string,
+	lock *Lock, platform string,
+) (LockedTool, error) {
+	dargs := toolArgs(ctx, spec, args.BinDir)
+	dargs.Parallel = args.Parallel
+	// The locked tag is kept for every platform, while the digests are only
+	// verified on the platforms already pinned.
+	locked, found := lock.Find(spec)
+	if found {
+		dargs.Tag = locked.Tag
+	}
+	digests, ok := locked.Digests(platform)
+	if ok {
+		dargs.Digests = digests
+	}
+	if !ok && args.Frozen {
+		return LockedTool{}, errors.WithStack(fmt.Errorf("%w: %s isn't locked for %s",
+			ErrOutdatedLock, spec, platform))
+	}
+	res, err := installer.Install(ctx, dargs)
+	if err != nil {
+		return LockedTool{}, err
+	}
+	tool := LockedTool{
+		Spec:      spec,
+		Tag:       res.Tag,
+		Platforms: make(map[string][]LockedAsset, len(locked.Platforms)+1),
+	}
+	if locked.Tag == res.Tag {
+		for p, assets := range locked.Platforms {
+			tool.Platforms[p] = assets
+		}
+	}
+	tool.Platforms[platform] = lockedAssets(res)
+	return tool, nil
+}
+
+func toolArgs(ctx context.Context, spec, binDir string) download.Args {
+	cfg := config.FromContext(ctx)
+	a := install.Parse(spec)
+	a.Site = cfg.Site(a.Address)
+	a.PreRelease = cfg.PreReleases()
+	return download.Args{
+		Args:        a.WithDefaults(),
+		Destination: binDir,
+	}
+}
+
+func lockedAssets(res *download.Result) []LockedAsset {
+	assets := make([]LockedAsset, 0, len(res.Assets))
+	for _, a := range res.Assets {
+		assets = append(assets, LockedAsset{
+			Name:   a.Name,
+			URL:    a.URL,
+			SHA256: res.Digests[a.Name],
+		})
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Name < assets[j].Name
+	})
+	return assets
+}
+
+func verifyFrozen(m *Manifest, lock *Lock) error {
+	specs := make(map[string]bool, len(m.Tools))
+	for _, spec := range m.Tools {
+		specs[spec] = true
+	}
+	for _, t := range lock.Tools {
+		if !specs[t.Spec] {
+			return errors.WithStack(fmt.Errorf("%w: %s isn't in %s",
+				ErrOutdatedLock, t.Spec, FileName))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+//go:build !race
+
+package manifest_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/manifest"
+	"github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	gh "github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+	"knative.dev/client/pkg/output/logging"
+)
+
+const (
+	lockedTag    = "v1.0.0"
+	lockedBinary = "#!/bin/sh\necho v1.0.0\n"
+	latestBinary = "#!/bin/sh\necho v1.1.0\n"
+)
+
+func TestAction(t *testing.T) {
+	t.Parallel()
+	tcs := []syncTestCase{{
+		name: "resolves and locks",
+		want: latestBinary,
+		tag:  "v1.1.0",
+	}, {
+		name:   "installs as locked",
+		locked: lockedBinary,
+		want:   lockedBinary,
+		tag:    lockedTag,
+	}, {
+		name:          "keeps the tag locked on another platform",
+		locked:        lockedBinary,
+		otherPlatform: true,
+		want:          lockedBinary,
+		tag:           lockedTag,
+	}, {
+		name:    "locked digest mismatch",
+		locked:  "tampered",
+		wantErr: download.ErrDigestMismatch,
+	}, {
+		name:    "locked asset not planned",
+		locked:  lockedBinary,
+		extra:   "agg-checksums.txt",
+		wantErr: download.ErrDigestMismatch,
+	}, {
+		name:    "frozen without lock",
+		frozen:  true,
+		wantErr: manifest.ErrOutdatedLock,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc syncTestCase) run(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	ctx = configdir.WithCacheDir(ctx, t.TempDir())
+	ctx = configdir.WithVersionsDir(ctx, t.TempDir())
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	dir := t.TempDir()
+	bindir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(dir, manifest.FileName),
+		[]byte("tools:\n  - asciinema/agg\n"), 0o600))
+	if tc.locked != "" {
+		require.NoError(t, writeLock(dir, tc.locked, tc.lockedPlatform(), tc.extra))
+	}
+
+	ghapi.WithTestClient(t, func(client *gh.Client, mux *http.ServeMux) {
+		ctx = ghapi.WithContext(ctx, client)
+		configureMux(mux, client.BaseURL.String())
+
+		err := manifest.Action(ctx, manifest.Args{
+			Dir:    dir,
+			BinDir: bindir,
+			Frozen: tc.frozen,
+		})
+		if tc.wantErr != nil {
+			require.ErrorIs(t, err, tc.wantErr)
+			return
+		}
+		require.NoError(t, err)
+	})
+	if tc.wantErr != nil {
+		return
+	}
+
+	bytes, err := os.ReadFile(path.Join(bindir, "agg"))
+	require.NoError(t, err)
+	assert.Equal(t, tc.want, string(bytes))
+	lock, err := manifest.LoadLock(dir)
+	require.NoError(t, err)
+	tool, ok := lock.Find("asciinema/agg")
+	require.True(t, ok)
+	assert.Equal(t, tc.tag, tool.Tag)
+	digests, ok := tool.Digests(manifest.CurrentPlatform())
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{assetName(): sha256Of(tc.want)}, digests)
+	if tc.otherPlatform {
+		_, ok = tool.Digests(otherPlatform())
+		assert.True(t, ok, "the pins of the other platform are kept")
+	}
+}
+
+func (tc syncTestCase) lockedPlatform() string {
+	if tc.otherPlatform {
+		return otherPlatform()
+	}
+	return manifest.CurrentPlatform()
+}
+
+// otherPlatform is a platform different from the running one.
+func otherPlatform() string {
+	if p := "darwin/arm64"; p != manifest.CurrentPlatform() {
+		return p
+	}
+	return "linux/amd64"
+}
+
+// writeLock locks the asset to the digest of the content, and pins the
+// extra assets, if any.
+func writeLock(dir, content, platform string, extra ...string) error {
+	assets := make([]manifest.LockedAsset, 0, len(extra)+1)
+	for _, name := range append([]string{assetName()}, extra...) {
+		if name == "" {
+			continue
+		}
+		assets = append(assets, manifest.LockedAsset{
+			Name:   name,
+			URL:    "https://example.org/" + name,
+			SHA256: sha256Of(content),
+		})
+	}
+	lock := &manifest.Lock{Tools: []manifest.LockedTool{{
+		Spec:      "asciinema/agg",
+		Tag:       lockedTag,
+		Platforms: map[string][]manifest.LockedAsset{platform: assets},
+	}}}
+	return lock.Save(dir)
+}
+
+func configureMux(mux *http.ServeMux, baseURL string) {
+	asset := assetName()
+	release := func(tag string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"tag_name": %q, "assets": [
+				{"id": 1, "name": %q, "size": %d, "content_type": "application/octet-stream",
+				 "browser_download_url": "%sdownload/%s/%s"}
+			]}`, tag, asset, len(latestBinary), baseURL, tag, asset)
+		}
+	}
+	mux.HandleFunc("/repos/asciinema/agg/releases/latest", release("v1.1.0"))
+	mux.HandleFunc("/repos/asciinema/agg/releases/tags/"+lockedTag, release(lockedTag))
+	mux.HandleFunc("/download/v1.1.0/"+asset, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(latestBinary))
+	})
+	mux.HandleFunc("/download/"+lockedTag+"/"+asset, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(lockedBinary))
+	})
+}
+
+func assetName() string {
+	return fmt.Sprintf("agg-%s-%s", github.CurrentOS(), github.CurrentArchitecture())
+}
+
+func sha256Of(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+type syncTestCase struct {
+	name          string
+	locked        string
+	otherPlatform bool
+	// extra is the name of an asset pinned by the lock, but not planned.
+	extra   string
+	frozen  bool
+	want    string
+	tag     string
+	wantErr error
+}
@@ -0,0 +1,109 @@
+package remove
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+// ErrModifiedBinary is returned when the installed binary was modified after
+// the installation.
+var ErrModifiedBinary = errors.New("binary was modified after installation")
+
+type Args struct {
+	// Tools to remove, given as owner/repo or as a name of installed binary.
+	Tools  []string
+	Force  bool
+	DryRun bool
+}
+
+func Action(ctx context.Context, args Args) error {
+	ctx = logging.EnsureLogger(ctx)
+	store := state.New(ctx)
+	db, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	insts, err := db.Resolve(args.Tools)
+	if err != nil {
+		return err
+	}
+	for _, inst := range insts {
+		if err = verify(ctx, inst, args.Force); err != nil {
+			return err
+		}
+	}
+
+	widgets := tui.NewWidgets(ctx)
+	if args.DryRun {
+		for _, inst := range insts {
+			for _, fp := range filesOf(ctx, inst) {
+				widgets.Printf("🗑️ Would remove %s", color.Cyan.Sprint(fp))
+			}
+		}
+		return nil
+	}
+	for _, inst := range insts {
+		installer.Unlink(ctx, inst.Links)
+		for _, v := range inst.Versions {
+			if err = os.RemoveAll(installer.VersionDir(ctx, inst.Repository, v.Tag)); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		// Only succeeds if no other versions are left there.
+		_ = os.Remove(installer.RepoDir(ctx, inst.Repository))
+		for _, fp := range filesOf(ctx, inst) {
+			widgets.Printf("🗑️ Removed %s", color.Cyan.Sprint(fp))
+		}
+	}
+	return store.Update(ctx, func(db *state.Database) error {
+		for _, inst := range insts {
+			db.Delete(inst.Repository)
+		}
+		return nil
+	})
+}
+
+// filesOf returns the links and the version directories of the installation.
+func filesOf(ctx context.Context, inst state.Installation) []string {
+	files := make([]string, 0, len(inst.Links)+len(inst.Versions))
+	files = append(files, inst.Links...)
+	for _, v := range inst.Versions {
+		files = append(files, installer.VersionDir(ctx, inst.Repository, v.Tag))
+	}
+	return files
+}
+
+// verify checks the binaries of all the installed versions weren't modified.
+func verify(ctx context.Context, inst state.Installation, force bool) error {
+	l := logging.LoggerFrom(ctx).
+		WithFields(logging.Fields{"repo": inst.Repository.String()})
+	for _, v := range inst.Versions {
+		for _, bin := range v.Binaries {
+			sum, err := state.Checksum(bin.Path)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					l.Warnf("Binary is already gone: %s", bin.Path)
+					continue
+				}
+				return err
+			}
+			if sum != bin.SHA256 {
+				if !force {
+					return errors.WithStack(fmt.Errorf(
+						"%w: %s (use --force to remove anyway)",
+						ErrModifiedBinary, bin.Path))
+				}
+				l.Warnf("Removing modified binary: %s", bin.Path)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package remove_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/remove"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestAction(t *testing.T) {
+	t.Parallel()
+	tcs := []actionTestCase{{
+		name:      "by repository",
+		args:      remove.Args{Tools: []string{"gohugoio/hugo"}},
+		gone:      []string{"hugo"},
+		goneRepos: []artifact.Repository{{Owner: "gohugoio", Repo: "hugo"}},
+		kept:      []string{"kubectl", "kubectx", "kubens"},
+	}, {
+		name: "all binaries by binary name",
+		args: remove.Args{Tools: []string{"kubens"}},
+		gone: []string{"kubectx", "kubens"},
+		kept: []string{"hugo", "kubectl"},
+	}, {
+		name: "dry run",
+		args: remove.Args{Tools: []string{"hugo"}, DryRun: true},
+		kept: []string{"hugo", "kubectl", "kubectx", "kubens"},
+	}, {
+		name:    "modified",
+		args:    remove.Args{Tools: []string{"kubernetes/kubectl"}},
+		kept:    []string{"hugo", "kubectl", "kubectx", "kubens"},
+		wantErr: remove.ErrModifiedBinary,
+	}, {
+		name: "modified with force",
+		args: remove.Args{Tools: []string{"kubectl"}, Force: true},
+		gone: []string{"kubectl"},
+		kept: []string{"hugo", "kubectx", "kubens"},
+	}, {
+		name:    "not installed",
+		args:    remove.Args{Tools: []string{"k9s"}},
+		kept:    []string{"hugo", "kubectl", "kubectx", "kubens"},
+		wantErr: state.ErrNotInstalled,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc actionTestCase) run(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	ctx = configdir.WithVersionsDir(ctx, t.TempDir())
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	bindir := t.TempDir()
+	installFixtures(ctx, t, bindir)
+
+	err := remove.Action(ctx, tc.args)
+	require.ErrorIs(t, err, tc.wantErr)
+	for _, name := range tc.gone {
+		assert.NoFileExists(t, path.Join(bindir, name))
+	}
+	for _, repo := range tc.goneRepos {
+		assert.NoDirExists(t, installer.RepoDir(ctx, repo))
+	}
+	for _, name := range tc.kept {
+		assert.FileExists(t, path.Join(bindir, name))
+	}
+	db, err := state.New(ctx).Load(ctx)
+	require.NoError(t, err)
+	for _, name := range tc.gone {
+		assert.Empty(t, db.Lookup(name))
+	}
+	for _, name := range tc.kept {
+		assert.Len(t, db.Lookup(name), 1)
+	}
+}
+
+func installFixtures(ctx context.Context, t *testing.T, bindir string) {
+	tools := map[artifact.Repository][]string{
+		{Owner: "gohugoio", Repo: "hugo"}:      {"hugo"},
+		{Owner: "kubernetes", Repo: "kubectl"}: {"kubectl"},
+		{Owner: "ahmetb", Repo: "kubectx"}:     {"kubectx", "kubens"},
+	}
+	require.NoError(t, state.New(ctx).Update(ctx, func(db *state.Database) error {
+		for repo, bins := range tools {
+			inst := state.Installation{Repository: repo, Tag: "v1.0.0"}
+			v := state.InstalledVersion{Tag: inst.Tag}
+			dir := installer.VersionDir(ctx, repo, v.Tag)
+			require.NoError(t, os.MkdirAll(dir, 0o750))
+			for _, name := range bins {
+				fp := path.Join(dir, name)
+				require.NoError(t, os.WriteFile(fp, []byte(name), 0o600))
+				bin, err := state.NewBinary(fp)
+				require.NoError(t, err)
+				v.Binaries = append(v.Binaries, bin)
+				link := path.Join(bindir, name)
+				require.NoError(t, os.Symlink(fp, link))
+				inst.Links = append(inst.Links, link)
+			}
+			inst.Versions = append(inst.Versions, v)
+			db.Put(inst)
+		}
+		return nil
+	}))
+	// Simulate a user modifying the installed binary.
+	require.NoError(t, os.WriteFile(path.Join(bindir, "kubectl"),
+		[]byte("patched"), 0o600))
+}
+
+type actionTestCase struct {
+	name      string
+	args      remove.Args
+	gone      []string
+	goneRepos []artifact.Repository
+	kept      []string
+	wantErr   error
+}
@@ -0,0 +1,22 @@
+package shim
+
+import (
+	"context"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+// Exec runs the binary resolved for the current working directory with the
+// given arguments. On success, it doesn't return on systems supporting exec.
+func Exec(ctx context.Context, binary string, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fp, err := Resolve(ctx, binary, wd)
+	if err != nil {
+		return err
+	}
+	return execute(fp, append([]string{binary}, args...))
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package shim
+
+import (
+	"os"
+	"syscall"
+
+	"emperror.dev/errors"
+)
+
+func execute(fp string, argv []string) error {
+	return errors.WithStack(syscall.Exec(fp, argv, os.Environ()))
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package shim
+
+import (
+	"os"
+	"os/exec"
+
+	"emperror.dev/errors"
+)
+
+// execute runs the binary as a child process, as Windows can't replace the
+// current one, and exits with its exit code.
+func execute(fp string, argv []string) error {
+	cmd := exec.Command(fp, argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err := cmd.Run()
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		os.Exit(ee.ExitCode())
+	}
+	return errors.WithStack(err)
+}
@@ -0,0 +1,119 @@
+package shim
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	"github.com/cardil/ghet/pkg/ghet/manifest"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/cardil/ghet/pkg/github"
+	"knative.dev/client/pkg/output/logging"
+)
+
+// Resolve returns the path to the binary of the version pinned by the project
+// manifest nearest to the given directory. If there is no such project, or
+// it doesn't pin the tool, the active version is used.
+func Resolve(ctx context.Context, binary, dir string) (string, error) {
+	ctx = logging.EnsureLogger(ctx)
+	db, err := state.New(ctx).Load(ctx)
+	if err != nil {
+		return "", err
+	}
+	insts, err := db.Resolve([]string{binary})
+	if err != nil {
+		return "", err
+	}
+	inst := insts[0]
+	tag, err := pinnedTag(ctx, inst, dir)
+	if err != nil {
+		return "", err
+	}
+	if tag == "" {
+		tag = inst.Tag
+	}
+	logging.LoggerFrom(ctx).WithFields(logging.Fields{
+		"binary": binary,
+		"repo":   inst.Repository.String(),
+		"tag":    tag,
+	}).Debug("Resolved shim")
+	if v, ok := inst.FindVersion(tag); ok {
+		for _, bin := range v.Binaries {
+			if path.Base(bin.Path) == binary {
+				return bin.Path, nil
+			}
+		}
+	}
+	return "", errors.WithStack(fmt.Errorf(
+		"%w: %s of %s@%s (run ght sync to install it)",
+		state.ErrNotInstalled, binary, inst.Repository, tag))
+}
+
+// pinnedTag returns the tag the nearest project pins the tool to, or an
+// empty string, if it doesn't.
+func pinnedTag(ctx context.Context, inst state.Installation, dir string) (string, error) {
+	project, ok := manifest.FindProject(dir)
+	if !ok {
+		return "", nil
+	}
+	logging.LoggerFrom(ctx).Debugf("Using project: %s", project)
+	lock, err := manifest.LoadLock(project)
+	if err != nil {
+		return "", err
+	}
+	for _, tool := range lock.Tools {
+		if install.Parse(tool.Spec).Repository == inst.Repository {
+			return tool.Tag, nil
+		}
+	}
+	if _, err = os.Stat(path.Join(project, manifest.FileName)); err != nil {
+		return "", nil //nolint:nilerr
+	}
+	m, err := manifest.Load(project)
+	if err != nil {
+		return "", err
+	}
+	for _, spec := range m.Tools {
+		args := install.Parse(spec)
+		if args.Repository == inst.Repository {
+			return matchingTag(inst, args.Tag)
+		}
+	}
+	return "", nil
+}
+
+// matchingTag returns the highest installed version, matching the version of
+// the manifest, which wasn't locked yet.
+func matchingTag(inst state.Installation, version string) (string, error) {
+	switch {
+	case version == github.LatestTag:
+		return "", nil
+	case !github.IsConstraint(version):
+		return version, nil
+	}
+	constraint, err := github.ParseConstraint(version)
+	if err != nil {
+		return "", err
+	}
+	var (
+		best    string
+		bestVer *semver.Version
+	)
+	for _, v := range inst.Versions {
+		ver, verr := github.ParseTagVersion(v.Tag)
+		if verr != nil || !constraint.Check(ver) {
+			continue
+		}
+		if bestVer == nil || ver.GreaterThan(bestVer) {
+			best, bestVer = v.Tag, ver
+		}
+	}
+	if best == "" {
+		return version, nil
+	}
+	return best, nil
+}
@@ -0,0 +1,125 @@
+package shim_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/manifest"
+	"github.com/cardil/ghet/pkg/ghet/shim"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	tcs := []resolveTestCase{{
+		name: "no project",
+		want: "v1.30.0",
+	}, {
+		name:     "locked in parent directory",
+		lock:     "v1.28.4",
+		manifest: "kubernetes/kubectl@^1.28",
+		want:     "v1.28.4",
+	}, {
+		name:     "manifest constraint",
+		manifest: "kubernetes/kubectl@~1.29",
+		want:     "v1.29.1",
+	}, {
+		name:     "manifest exact tag",
+		manifest: "kubernetes/kubectl@v1.28.4",
+		want:     "v1.28.4",
+	}, {
+		name:     "not pinned by project",
+		manifest: "derailed/k9s",
+		want:     "v1.30.0",
+	}, {
+		name:     "pinned but not installed",
+		lock:     "v1.27.0",
+		manifest: "kubernetes/kubectl",
+		wantErr:  state.ErrNotInstalled,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc resolveTestCase) run(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	versions := t.TempDir()
+	require.NoError(t, state.New(ctx).Update(ctx, func(db *state.Database) error {
+		inst := state.Installation{
+			Repository: artifact.Repository{Owner: "kubernetes", Repo: "kubectl"},
+			Tag:        "v1.30.0",
+			Links:      []string{"/usr/local/bin/kubectl"},
+		}
+		for _, tag := range []string{"v1.28.4", "v1.29.1", "v1.30.0"} {
+			inst.Versions = append(inst.Versions, state.InstalledVersion{
+				Tag:      tag,
+				Binaries: []state.Binary{{Path: path.Join(versions, tag, "kubectl")}},
+			})
+		}
+		db.Put(inst)
+		return nil
+	}))
+	project := t.TempDir()
+	if tc.manifest != "" {
+		require.NoError(t, os.WriteFile(path.Join(project, manifest.FileName),
+			[]byte("tools:\n  - "+tc.manifest+"\n"), 0o600))
+	}
+	if tc.lock != "" {
+		lock := &manifest.Lock{Tools: []manifest.LockedTool{{
+			Spec: tc.manifest,
+			Tag:  tc.lock,
+		}}}
+		require.NoError(t, lock.Save(project))
+	}
+	wd := path.Join(project, "cmd", "app")
+	require.NoError(t, os.MkdirAll(wd, 0o750))
+
+	got, err := shim.Resolve(ctx, "kubectl", wd)
+	if tc.wantErr != nil {
+		require.ErrorIs(t, err, tc.wantErr)
+		return
+	}
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(versions, tc.want, "kubectl"), got)
+}
+
+func TestResolveThroughCmdShim(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	bin := path.Join(t.TempDir(), "v1.30.0", "kubectl.exe")
+	require.NoError(t, state.New(ctx).Update(ctx, func(db *state.Database) error {
+		db.Put(state.Installation{
+			Repository: artifact.Repository{Owner: "kubernetes", Repo: "kubectl"},
+			Tag:        "v1.30.0",
+			Links:      []string{"C:/Users/ght/bin/kubectl.cmd"},
+			Versions: []state.InstalledVersion{{
+				Tag:      "v1.30.0",
+				Binaries: []state.Binary{{Path: bin}},
+			}},
+		})
+		return nil
+	}))
+
+	got, err := shim.Resolve(ctx, "kubectl.exe", t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, bin, got)
+}
+
+type resolveTestCase struct {
+	name     string
+	manifest string
+	lock     string
+	want     string
+	wantErr  error
+}
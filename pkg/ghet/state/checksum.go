@@ -0,0 +1,34 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+// NewBinary creates a binary record for the given file, computing its
+// checksum.
+func NewBinary(fp string) (Binary, error) {
+	sum, err := Checksum(fp)
+	if err != nil {
+		return Binary{}, err
+	}
+	return Binary{Path: fp, SHA256: sum}, nil
+}
+
+// Checksum computes the SHA-256 checksum of the given file.
+func Checksum(fp string) (string, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer f.Close()
+	dig := sha256.New()
+	if _, err = io.Copy(dig, f); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return hex.EncodeToString(dig.Sum(nil)), nil
+}
@@ -0,0 +1,255 @@
+package state
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/install"
+	"github.com/cardil/ghet/pkg/github"
+)
+
+// ErrNotInstalled is returned when the tool isn't installed.
+var ErrNotInstalled = errors.New("not installed")
+
+// ErrAmbiguousTool is returned when the tool name matches more than one
+// installation.
+var ErrAmbiguousTool = errors.New("ambiguous tool name")
+
+// Database holds the records of all the tools installed by ght.
+type Database struct {
+	Installations []Installation `json:"installations"`
+}
+
+// Installation is a record of a single tool installed by ght, along with all
+// of its installed versions.
+type Installation struct {
+	artifact.Repository
+	Site             string `json:"site,omitempty"`
+	Version          string `json:"version,omitempty"`
+	BaseName         string `json:"basename,omitempty"`
+	Archive          string `json:"archive,omitempty"`
+	Checksums        string `json:"checksums,omitempty"`
+	MultipleBinaries bool   `json:"multipleBinaries,omitempty"`
+	VerifyInArchive  bool   `json:"verifyInArchive,omitempty"`
+	PreRelease       bool   `json:"prerelease,omitempty"`
+	// Tag is the active version, the links point to.
+	Tag string `json:"tag"`
+	// Links are the symbolic links in the bin directory, pointing to the
+	// binaries of the active version, or the shims if enabled.
+	Links []string `json:"links,omitempty"`
+	// Shims are placed in the bin directory instead of the links, if set. The
+	// shims pick the version pinned by the nearest project manifest.
+	Shims    bool               `json:"shims,omitempty"`
+	Versions []InstalledVersion `json:"versions"`
+}
+
+// InstalledVersion is a single version of the tool, kept in its own directory.
+type InstalledVersion struct {
+	Tag         string           `json:"tag"`
+	Assets      []artifact.Asset `json:"assets"`
+	Binaries    []Binary         `json:"binaries"`
+	InstalledAt time.Time        `json:"installedAt"`
+}
+
+// Binary is a file placed on disk during the installation.
+type Binary struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Find returns the installation of the given repository.
+func (db *Database) Find(repo artifact.Repository) (Installation, bool) {
+	for _, inst := range db.Installations {
+		if inst.Repository == repo {
+			return inst, true
+		}
+	}
+	return Installation{}, false
+}
+
+// Put adds, or replaces, the installation of the given repository.
+func (db *Database) Put(inst Installation) {
+	for i, curr := range db.Installations {
+		if curr.Repository == inst.Repository {
+			db.Installations[i] = inst
+			return
+		}
+	}
+	db.Installations = append(db.Installations, inst)
+}
+
+// Add records the installation, keeping the other versions of the repository
+// that are already installed.
+func (db *Database) Add(inst Installation) {
+	if curr, ok := db.Find(inst.Repository); ok {
+		for _, v := range curr.Versions {
+			if _, found := inst.FindVersion(v.Tag); !found {
+				inst.Versions = append(inst.Versions, v)
+			}
+		}
+	}
+	db.Put(inst)
+}
+
+// Delete removes the installation of the given repository. It returns false,
+// if there was no such installation.
+func (db *Database) Delete(repo artifact.Repository) bool {
+	for i, curr := range db.Installations {
+		if curr.Repository == repo {
+			db.Installations = append(db.Installations[:i], db.Installations[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// NewInstallation creates a record of the installation performed with the
+// given arguments.
+func NewInstallation(args download.Args, res *download.Result) (Installation, error) {
+	binaries := make([]Binary, 0, len(res.Binaries))
+	for _, fp := range res.Binaries {
+		bin, err := NewBinary(fp)
+		if err != nil {
+			return Installation{}, err
+		}
+		binaries = append(binaries, bin)
+	}
+	return Installation{
+		Repository:       args.Repository,
+		Site:             args.Address,
+		Version:          args.Tag,
+		BaseName:         args.ToString(),
+		Archive:          args.Archive,
+		Checksums:        args.Checksums.ToString(),
+		MultipleBinaries: args.MultipleBinaries,
+		VerifyInArchive:  args.VerifyInArchive,
+		PreRelease:       args.PreRelease,
+		Tag:              res.Tag,
+		Versions: []InstalledVersion{{
+			Tag:         res.Tag,
+			Assets:      res.Assets,
+			Binaries:    binaries,
+			InstalledAt: time.Now().UTC().Truncate(time.Second),
+		}},
+	}, nil
+}
+
+// Lookup finds the installations matching the given tool, which can be given
+// either as owner/repo or as a name of one of the installed binaries.
+func (db *Database) Lookup(tool string) []Installation {
+	found := make([]Installation, 0, 1)
+	for _, inst := range db.Installations {
+		if inst.Repository.String() == tool || inst.providesBinary(tool) {
+			found = append(found, inst)
+		}
+	}
+	return found
+}
+
+// Resolve finds exactly one installation for each of the given tools.
+func (db *Database) Resolve(tools []string) ([]Installation, error) {
+	insts := make([]Installation, 0, len(tools))
+	for _, tool := range tools {
+		found := db.Lookup(tool)
+		switch len(found) {
+		case 0:
+			return nil, errors.WithStack(fmt.Errorf("%w: %s", ErrNotInstalled, tool))
+		case 1:
+			insts = append(insts, found[0])
+		default:
+			repos := make([]string, 0, len(found))
+			for _, inst := range found {
+				repos = append(repos, inst.Repository.String())
+			}
+			return nil, errors.WithStack(fmt.Errorf("%w: %s is provided by %s",
+				ErrAmbiguousTool, tool, strings.Join(repos, ", ")))
+		}
+	}
+	return insts, nil
+}
+
+// Wanted returns the version the installation should be kept at: either the
+// version constraint it was installed with, or the latest release.
+func (inst Installation) Wanted() string {
+	if github.IsConstraint(inst.Version) {
+		return inst.Version
+	}
+	return github.LatestTag
+}
+
+// Args returns the arguments that reproduce the installation from the given
+// site, for the given tag.
+func (inst Installation) Args(site config.Site, tag string) install.Args {
+	args := install.Args{
+		Asset: github.Asset{
+			FileName: github.NewFileName(inst.BaseName),
+			Archive:  inst.Archive,
+			Release: github.Release{
+				Tag:        tag,
+				Repository: inst.Repository,
+			},
+			Checksums: github.Checksums{
+				FileName: github.NewFileName(inst.Checksums),
+			},
+		},
+		Site:             site,
+		MultipleBinaries: inst.MultipleBinaries,
+		VerifyInArchive:  inst.VerifyInArchive,
+		PreRelease:       inst.PreRelease,
+	}
+	return args.WithDefaults()
+}
+
+// Active returns the active version of the installation.
+func (inst Installation) Active() InstalledVersion {
+	v, _ := inst.FindVersion(inst.Tag)
+	return v
+}
+
+// FindVersion returns the installed version with the given tag.
+func (inst Installation) FindVersion(tag string) (InstalledVersion, bool) {
+	for _, v := range inst.Versions {
+		if v.Tag == tag {
+			return v, true
+		}
+	}
+	return InstalledVersion{}, false
+}
+
+// DeleteVersion removes the installed version with the given tag. It returns
+// false, if there was no such version.
+func (inst *Installation) DeleteVersion(tag string) bool {
+	for i, v := range inst.Versions {
+		if v.Tag == tag {
+			inst.Versions = append(inst.Versions[:i], inst.Versions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// providesBinary tells whether one of the links is of the given binary. The
+// shims on Windows are linked as "kubectl.cmd", in place of the "kubectl.exe"
+// binary they run, so their extension is ignored.
+func (inst Installation) providesBinary(name string) bool {
+	for _, link := range inst.Links {
+		base := path.Base(link)
+		if base == name || (path.Ext(base) == cmdExt && stem(base) == stem(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cmdExt is the extension of the shims on Windows.
+const cmdExt = ".cmd"
+
+func stem(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name))
+}
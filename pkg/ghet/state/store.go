@@ -0,0 +1,146 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"knative.dev/client/pkg/output/logging"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidDatabase is returned when the installation database can't be read.
+var ErrInvalidDatabase = errors.New("invalid installation database")
+
+// ErrLocked is returned when the installation database is locked by another
+// process for too long.
+var ErrLocked = errors.New("installation database is locked")
+
+const (
+	databaseFile      = "installed.yaml"
+	fileMode          = 0o600
+	lockTimeout       = 30 * time.Second
+	lockRetryInterval = 50 * time.Millisecond
+	staleLockAge      = 10 * time.Minute
+)
+
+// Store persists the installation database on disk. It's safe to use from
+// multiple processes at once.
+type Store struct {
+	path string
+}
+
+// New returns a store kept in the configuration directory.
+func New(ctx context.Context) Store {
+	return Store{path: path.Join(configdir.Config(ctx), databaseFile)}
+}
+
+// Load reads the current state of the installation database.
+func (s Store) Load(ctx context.Context) (Database, error) {
+	l := logging.LoggerFrom(ctx).
+		WithFields(logging.Fields{"databasePath": s.path})
+	bytes, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			l.Debug("Installation database does not exist, using empty one")
+			return Database{}, nil
+		}
+		return Database{}, asInvalidDatabaseErr(err)
+	}
+	var db Database
+	if err = yaml.Unmarshal(bytes, &db); err != nil {
+		return Database{}, asInvalidDatabaseErr(err)
+	}
+	return db, nil
+}
+
+// Update modifies the installation database with the given function. The
+// database is locked for the time of the update, so concurrent updates made
+// by other processes are not lost.
+func (s Store) Update(ctx context.Context, fn func(db *Database) error) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	db, err := s.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if err = fn(&db); err != nil {
+		return err
+	}
+	return s.save(db)
+}
+
+func (s Store) save(db Database) error {
+	bytes, err := yaml.Marshal(db)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmp, err := os.CreateTemp(path.Dir(s.path), "."+databaseFile+"-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = tmp.Write(bytes); err != nil {
+		_ = tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err = tmp.Chmod(fileMode); err != nil {
+		_ = tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp.Name(), s.path))
+}
+
+func (s Store) lock(ctx context.Context) (func(), error) {
+	l := logging.LoggerFrom(ctx)
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()))
+			_ = f.Close()
+			return func() {
+				_ = os.Remove(lockPath)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.WithStack(err)
+		}
+		if fi, serr := os.Stat(lockPath); serr == nil && time.Since(fi.ModTime()) > staleLockAge {
+			l.Warnf("Removing stale lock: %s", lockPath)
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.WithStack(fmt.Errorf("%w: %s", ErrLocked, lockPath))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.WithStack(ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func asInvalidDatabaseErr(err error) error {
+	if errors.Is(err, ErrInvalidDatabase) {
+		return err
+	}
+	return errors.WithStack(
+		errors.Wrap(ErrInvalidDatabase, fmt.Sprintf("%+v", err)),
+	)
+}
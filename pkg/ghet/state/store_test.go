@@ -0,0 +1,112 @@
+package state_test
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := testContext(t)
+	store := state.New(ctx)
+	repo := artifact.Repository{Owner: "cardil", Repo: "ghet"}
+	bin := path.Join(t.TempDir(), "ght")
+	require.NoError(t, os.WriteFile(bin, []byte("ght"), 0o600))
+	b, err := state.NewBinary(bin)
+	require.NoError(t, err)
+	inst := state.Installation{
+		Repository: repo,
+		Tag:        "v0.3.0",
+		Links:      []string{path.Join(t.TempDir(), "ght")},
+		Versions: []state.InstalledVersion{{
+			Tag:         "v0.3.0",
+			Binaries:    []state.Binary{b},
+			InstalledAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+	}
+
+	require.NoError(t, store.Update(ctx, func(db *state.Database) error {
+		db.Put(inst)
+		return nil
+	}))
+	db, err := store.Load(ctx)
+	require.NoError(t, err)
+	got, ok := db.Find(repo)
+	require.True(t, ok)
+	assert.Equal(t, inst, got)
+	assert.Equal(t,
+		"aa3c8fcffe17445c69648cbc2e2e525096619a48963729a8b50e29479c256a65",
+		got.Active().Binaries[0].SHA256)
+
+	require.NoError(t, store.Update(ctx, func(db *state.Database) error {
+		assert.True(t, db.Delete(repo))
+		return nil
+	}))
+	db, err = store.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, db.Installations)
+}
+
+func TestDatabaseAddKeepsVersions(t *testing.T) {
+	t.Parallel()
+	repo := artifact.Repository{Owner: "kubernetes", Repo: "kubectl"}
+	db := state.Database{}
+	for _, tag := range []string{"v1.28.4", "v1.30.0", "v1.28.4"} {
+		db.Add(state.Installation{
+			Repository: repo,
+			Tag:        tag,
+			Versions:   []state.InstalledVersion{{Tag: tag}},
+		})
+	}
+	require.Len(t, db.Installations, 1)
+	inst := db.Installations[0]
+	assert.Equal(t, "v1.28.4", inst.Tag)
+	assert.Equal(t, "v1.28.4", inst.Active().Tag)
+	assert.Len(t, inst.Versions, 2)
+	assert.True(t, inst.DeleteVersion("v1.30.0"))
+	_, ok := inst.FindVersion("v1.30.0")
+	assert.False(t, ok)
+}
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+	ctx := testContext(t)
+	const workers = 16
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each worker uses its own store, like separate processes would.
+			store := state.New(ctx)
+			assert.NoError(t, store.Update(ctx, func(db *state.Database) error {
+				db.Put(state.Installation{Repository: artifact.Repository{
+					Owner: "owner", Repo: fmt.Sprintf("repo-%d", i),
+				}})
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	db, err := state.New(ctx).Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, db.Installations, workers)
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	return configdir.WithConfigDir(ctx, t.TempDir())
+}
@@ -0,0 +1,71 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/gookit/color"
+	"knative.dev/client/pkg/output/logging"
+	"knative.dev/client/pkg/output/tui"
+)
+
+type Args struct {
+	// Tools to upgrade, given as owner/repo or as a name of installed binary.
+	// All installed tools are upgraded, if empty.
+	Tools  []string
+	DryRun bool
+	// Parallel limits the number of assets downloaded at once.
+	Parallel int
+}
+
+// Action upgrades the outdated tools, and returns the updates applied, or the
+// ones that would be applied in the dry-run mode. If there were any, they are
+// returned alongside the ErrUpdatesAvailable, so the CI can gate on them.
+func Action(ctx context.Context, args Args) ([]Update, error) {
+	ctx = logging.EnsureLogger(ctx)
+	widgets := tui.NewWidgets(ctx)
+	updates, err := Outdated(ctx, args.Tools)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		widgets.Printf("🎉 All tools are up to date")
+		return updates, nil
+	}
+	if args.DryRun {
+		for _, u := range updates {
+			widgets.Printf("⬆️ Would upgrade %s from %s to %s",
+				color.Cyan.Sprint(u.Repository), u.Installed,
+				color.Cyan.Sprint(u.Latest))
+		}
+		return updates, errors.WithStack(fmt.Errorf("%w: %d",
+			ErrUpdatesAvailable, len(updates)))
+	}
+	for _, u := range updates {
+		if err = u.apply(ctx, args.Parallel); err != nil {
+			return nil, err
+		}
+		widgets.Printf("⬆️ Upgraded %s from %s to %s",
+			color.Cyan.Sprint(u.Repository), u.Installed,
+			color.Cyan.Sprint(u.Latest))
+	}
+	return updates, errors.WithStack(fmt.Errorf("%w: %d",
+		ErrUpdatesAvailable, len(updates)))
+}
+
+// apply installs the new release alongside the installed one, and switches
+// to it. The links are replaced atomically, so the tool stays usable at all
+// times, and the previous version is kept if anything fails.
+func (u Update) apply(ctx context.Context, parallel int) error {
+	cfg := config.FromContext(ctx)
+	args := download.Args{
+		Args:     u.installation.Args(cfg.Site(u.installation.Site), u.Latest),
+		Parallel: parallel,
+	}
+	_, err := installer.Upgrade(ctx, u.installation, args)
+	return err
+}
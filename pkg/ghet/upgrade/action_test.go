@@ -0,0 +1,163 @@
+//go:build !race
+
+package upgrade_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	configdir "github.com/cardil/ghet/pkg/config/dir"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/installer"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"github.com/cardil/ghet/pkg/ghet/upgrade"
+	"github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	gh "github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output"
+	"knative.dev/client/pkg/output/logging"
+)
+
+const (
+	oldBinary = "#!/bin/sh\necho v1.3.0\n"
+	newBinary = "#!/bin/sh\necho v1.4.0\n"
+)
+
+func TestAction(t *testing.T) {
+	t.Parallel()
+	tcs := []actionTestCase{{
+		name:    "upgraded",
+		want:    newBinary,
+		tag:     "v1.4.0",
+		wantErr: upgrade.ErrUpdatesAvailable,
+	}, {
+		name:     "checksum mismatch",
+		checksum: "0000000000000000000000000000000000000000000000000000000000000000",
+		want:     oldBinary,
+		tag:      "v1.3.0",
+		wantErr:  download.ErrChecksumMismatch,
+	}, {
+		name:    "dry run",
+		args:    upgrade.Args{DryRun: true},
+		want:    oldBinary,
+		tag:     "v1.3.0",
+		wantErr: upgrade.ErrUpdatesAvailable,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc actionTestCase) run(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+	ctx = configdir.WithConfigDir(ctx, t.TempDir())
+	ctx = configdir.WithCacheDir(ctx, t.TempDir())
+	ctx = configdir.WithVersionsDir(ctx, t.TempDir())
+	ctx = output.WithContext(ctx, output.NewTestPrinter())
+	bindir := t.TempDir()
+	repo := artifact.Repository{Owner: "asciinema", Repo: "agg"}
+	oldDir := installer.VersionDir(ctx, repo, "v1.3.0")
+	require.NoError(t, os.MkdirAll(oldDir, 0o750))
+	oldBin := path.Join(oldDir, "agg")
+	require.NoError(t, os.WriteFile(oldBin, []byte(oldBinary), 0o600))
+	record, err := state.NewBinary(oldBin)
+	require.NoError(t, err)
+	bin := path.Join(bindir, "agg")
+	require.NoError(t, os.Symlink(oldBin, bin))
+	require.NoError(t, state.New(ctx).Update(ctx, func(db *state.Database) error {
+		db.Put(state.Installation{
+			Repository: repo,
+			Tag:        "v1.3.0",
+			BaseName:   "agg",
+			Links:      []string{bin},
+			Versions: []state.InstalledVersion{{
+				Tag:      "v1.3.0",
+				Binaries: []state.Binary{record},
+			}},
+		})
+		return nil
+	}))
+
+	ghapi.WithTestClient(t, func(client *gh.Client, mux *http.ServeMux) {
+		ctx = ghapi.WithContext(ctx, client)
+		tc.configureMux(mux, client.BaseURL.String())
+
+		updates, err := upgrade.Outdated(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.4.0"}, latestOf(updates))
+
+		_, err = upgrade.Action(ctx, tc.args)
+		require.ErrorIs(t, err, tc.wantErr)
+	})
+
+	bytes, err := os.ReadFile(bin)
+	require.NoError(t, err)
+	assert.Equal(t, tc.want, string(bytes))
+	entries, err := os.ReadDir(bindir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "stale files left: %v", entries)
+	db, err := state.New(ctx).Load(ctx)
+	require.NoError(t, err)
+	inst, ok := db.Find(repo)
+	require.True(t, ok)
+	assert.Equal(t, tc.tag, inst.Tag)
+	assert.Len(t, inst.Versions, 1)
+	assert.Equal(t, []string{bin}, inst.Links)
+	if tc.tag != "v1.3.0" {
+		assert.NoDirExists(t, oldDir)
+	}
+}
+
+func (tc actionTestCase) configureMux(mux *http.ServeMux, baseURL string) {
+	asset := fmt.Sprintf("agg-%s-%s", github.CurrentOS(), github.CurrentArchitecture())
+	checksum := tc.checksum
+	if checksum == "" {
+		sum := sha256.Sum256([]byte(newBinary))
+		checksum = hex.EncodeToString(sum[:])
+	}
+	checksums := fmt.Sprintf("%s  %s\n", checksum, asset)
+	release := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"tag_name": "v1.4.0", "assets": [
+			{"id": 1, "name": %q, "size": %d, "content_type": "application/octet-stream",
+			 "browser_download_url": "%sdownload/%s"},
+			{"id": 2, "name": "checksums.txt", "size": %d, "content_type": "text/plain",
+			 "browser_download_url": "%sdownload/checksums.txt"}
+		]}`, asset, len(newBinary), baseURL, asset, len(checksums), baseURL)
+	}
+	mux.HandleFunc("/repos/asciinema/agg/releases/latest", release)
+	mux.HandleFunc("/repos/asciinema/agg/releases/tags/v1.4.0", release)
+	mux.HandleFunc("/download/"+asset, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(newBinary))
+	})
+	mux.HandleFunc("/download/checksums.txt", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(checksums))
+	})
+}
+
+func latestOf(updates []upgrade.Update) []string {
+	tags := make([]string, 0, len(updates))
+	for _, u := range updates {
+		tags = append(tags, u.Latest)
+	}
+	return tags
+}
+
+type actionTestCase struct {
+	name     string
+	args     upgrade.Args
+	checksum string
+	want     string
+	tag      string
+	wantErr  error
+}
@@ -0,0 +1,68 @@
+package upgrade
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/ghet/download"
+	"github.com/cardil/ghet/pkg/ghet/state"
+	"knative.dev/client/pkg/output/logging"
+)
+
+// ErrUpdatesAvailable is returned when some of the installed tools are
+// behind their latest releases.
+var ErrUpdatesAvailable = errors.New("updates available")
+
+// Update describes an installed tool that has a newer release available.
+type Update struct {
+	artifact.Repository
+	Installed string `json:"installed"`
+	Latest    string `json:"latest"`
+
+	installation state.Installation
+}
+
+// Outdated checks the given tools, or all the installed ones if none are
+// given, against their latest releases. Tools installed with a version
+// constraint are checked against the latest release matching it.
+func Outdated(ctx context.Context, tools []string) ([]Update, error) {
+	ctx = logging.EnsureLogger(ctx)
+	db, err := state.New(ctx).Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	insts := db.Installations
+	if len(tools) > 0 {
+		if insts, err = db.Resolve(tools); err != nil {
+			return nil, err
+		}
+	}
+	cfg := config.FromContext(ctx)
+	updates := make([]Update, 0, len(insts))
+	for _, inst := range insts {
+		args := download.Args{
+			Args: inst.Args(cfg.Site(inst.Site), inst.Wanted()),
+		}
+		args.PreRelease = args.PreRelease || cfg.PreReleases()
+		var latest string
+		if latest, err = download.ResolveTag(ctx, args); err != nil {
+			return nil, err
+		}
+		logging.LoggerFrom(ctx).WithFields(logging.Fields{
+			"repo":      inst.Repository.String(),
+			"installed": inst.Tag,
+			"latest":    latest,
+		}).Debug("Checked for updates")
+		if latest != inst.Tag {
+			updates = append(updates, Update{
+				Repository:   inst.Repository,
+				Installed:    inst.Tag,
+				Latest:       latest,
+				installation: inst,
+			})
+		}
+	}
+	return updates, nil
+}
@@ -3,30 +3,71 @@ package api
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"time"
 
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/retry"
 	"github.com/google/go-github/v48/github"
-	"golang.org/x/oauth2"
+	"knative.dev/client/pkg/output/logging"
 )
 
+const defaultAddress = "github.com"
+
 type clientKey struct{}
 
+// NewClient creates a client of github.com, authenticated with the given
+// token, or the one resolved from the environment, if not given.
 func NewClient(ctx context.Context, token string) *github.Client {
-	var httpClient *http.Client
-	if token != "" {
-		src := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		)
-		httpClient = oauth2.NewClient(ctx, src)
-	}
-
-	return github.NewClient(httpClient)
+	return ForSite(ctx, config.Site{
+		Type:    config.TypeGitHub,
+		Address: defaultAddress,
+		Auth:    &config.Auth{Token: token},
+	})
 }
 
-func FromContext(ctx context.Context) *github.Client {
+// ForSite returns a client of the given site, authenticated with the token
+// resolved for it. Sites other than github.com are accessed as GitHub
+// Enterprise Servers. The client set in the context takes precedence.
+func ForSite(ctx context.Context, site config.Site) *github.Client {
 	if cl, ok := ctx.Value(clientKey{}).(*github.Client); ok {
 		return cl
 	}
-	return NewClient(ctx, "")
+	transport := newAuthTransport(ctx, NewTransport(ctx), site)
+	cl := github.NewClient(&http.Client{Transport: transport})
+	if IsEnterprise(site) {
+		cl.BaseURL = enterpriseURL(site, "/api/v3/")
+		cl.UploadURL = enterpriseURL(site, "/api/uploads/")
+	}
+	return cl
+}
+
+// IsEnterprise returns true if the site is a GitHub Enterprise Server.
+func IsEnterprise(site config.Site) bool {
+	return siteAddress(site) != defaultAddress
+}
+
+func enterpriseURL(site config.Site, path string) *url.URL {
+	return &url.URL{Scheme: "https", Host: siteAddress(site), Path: path}
+}
+
+// NewTransport retries the failed requests according to the configured
+// policy.
+func NewTransport(ctx context.Context) http.RoundTripper {
+	t := retry.NewTransport(http.DefaultTransport,
+		config.FromContext(ctx).Retry.Policy())
+	log := logging.LoggerFrom(logging.EnsureLogger(ctx))
+	t.OnRetry = func(req *http.Request, wait time.Duration, reason string) {
+		log.Warnf("Retrying %s %s in %s: %s", req.Method, req.URL.Redacted(),
+			wait.Round(time.Millisecond), reason)
+	}
+	return t
+}
+
+// FromContext returns the client set in the context, or the client of
+// github.com, using the token resolved for it.
+func FromContext(ctx context.Context) *github.Client {
+	return ForSite(ctx, config.FromContext(ctx).Site(defaultAddress))
 }
 
 func WithContext(ctx context.Context, cl *github.Client) context.Context {
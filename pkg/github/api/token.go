@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+
+	"github.com/cardil/ghet/pkg/config"
+	"knative.dev/client/pkg/output/logging"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// TokenEnvNames are the environment variables the github.com token is read
+	// from, in the order of priority.
+	TokenEnvNames = []string{"GHET_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"} //nolint:gochecknoglobals
+	// EnterpriseTokenEnvNames are the environment variables the token of the
+	// GitHub Enterprise Server sites is read from, like the gh CLI does.
+	EnterpriseTokenEnvNames = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} //nolint:gochecknoglobals
+)
+
+const (
+	sourceSiteConfig = "site config"
+	sourceGhHosts    = "gh hosts.yml"
+)
+
+// ResolveToken returns the token for the given site, along with the source it
+// was resolved from. The token is taken from the site config, the
+// environment, or the gh CLI configuration for the exact host, in that order.
+// The github.com tokens of the environment are never sent to the GitHub
+// Enterprise Server sites, which use their own variables. An empty token is
+// returned, if there is none.
+func ResolveToken(site config.Site) (string, string) {
+	if token := site.EffectiveToken(); token != "" {
+		return token, sourceSiteConfig
+	}
+	if site.Type != "" && site.Type != config.TypeGitHub {
+		return "", ""
+	}
+	envNames := TokenEnvNames
+	if IsEnterprise(site) {
+		envNames = EnterpriseTokenEnvNames
+	}
+	for _, name := range envNames {
+		if token := os.Getenv(name); token != "" {
+			return token, "$" + name
+		}
+	}
+	if token := ghHostsToken(siteAddress(site)); token != "" {
+		return token, sourceGhHosts
+	}
+	return "", ""
+}
+
+type ghHost struct {
+	OAuthToken string `json:"oauth_token"`
+}
+
+// ghHostsToken reads the token the gh CLI keeps for the given host. Tokens
+// kept by gh in the system keyring aren't supported.
+func ghHostsToken(host string) string {
+	bytes, err := os.ReadFile(path.Join(ghConfigDir(), "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	hosts := map[string]ghHost{}
+	if err = yaml.Unmarshal(bytes, &hosts); err != nil {
+		return ""
+	}
+	return hosts[host].OAuthToken
+}
+
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return path.Join(dir, "gh")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return path.Join(dir, "GitHub CLI")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return path.Join(home, ".config", "gh")
+}
+
+func siteAddress(site config.Site) string {
+	if site.Address == "" {
+		return defaultAddress
+	}
+	return site.Address
+}
+
+// authTransport sets the header only on the requests made to the site, so the
+// token isn't leaked to the hosts the assets are redirected to.
+type authTransport struct {
+	base          http.RoundTripper
+	header, value string
+	hosts         map[string]bool
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.hosts[req.URL.Host] && req.Header.Get(t.header) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(t.header, t.value)
+	}
+	return t.base.RoundTrip(req) //nolint:wrapcheck
+}
+
+// NewAuthTransport sets the header to the given value on the requests made to
+// the given hosts.
+func NewAuthTransport(
+	base http.RoundTripper, header, value string, hosts ...string,
+) http.RoundTripper {
+	t := &authTransport{
+		base:   base,
+		header: header,
+		value:  value,
+		hosts:  make(map[string]bool, len(hosts)),
+	}
+	for _, host := range hosts {
+		t.hosts[host] = true
+	}
+	return t
+}
+
+func newAuthTransport(
+	ctx context.Context, base http.RoundTripper, site config.Site,
+) http.RoundTripper {
+	token, source := ResolveToken(site)
+	address := siteAddress(site)
+	logging.LoggerFrom(logging.EnsureLogger(ctx)).WithFields(logging.Fields{
+		"site":        address,
+		"tokenSource": source,
+	}).Debug("Resolved API token")
+	if token == "" {
+		return base
+	}
+	hosts := []string{address}
+	if address == defaultAddress {
+		hosts = append(hosts, "api."+address, "uploads."+address)
+	}
+	return NewAuthTransport(base, "Authorization", "Bearer "+token, hosts...)
+}
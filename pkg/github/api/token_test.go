@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+	"knative.dev/client/pkg/output/logging"
+)
+
+func TestResolveToken(t *testing.T) {
+	ghConfig := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(ghConfig, "hosts.yml"), []byte(
+		"github.com:\n    user: octocat\n    oauth_token: gho_hosts\n"), 0o600))
+	t.Setenv("GH_CONFIG_DIR", ghConfig)
+	for _, name := range append(api.TokenEnvNames, api.EnterpriseTokenEnvNames...) {
+		t.Setenv(name, "")
+	}
+	github := config.Site{Type: config.TypeGitHub, Address: "github.com"}
+
+	token, source := api.ResolveToken(github)
+	assert.Equal(t, "gho_hosts", token)
+	assert.Equal(t, "gh hosts.yml", source)
+
+	t.Setenv("GH_TOKEN", "gh_env")
+	t.Setenv("GITHUB_TOKEN", "github_env")
+	token, source = api.ResolveToken(github)
+	assert.Equal(t, "github_env", token)
+	assert.Equal(t, "$GITHUB_TOKEN", source)
+
+	t.Setenv("GHET_TOKEN", "ghet_env")
+	token, _ = api.ResolveToken(github)
+	assert.Equal(t, "ghet_env", token)
+
+	github.Auth = &config.Auth{Token: "site"}
+	token, source = api.ResolveToken(github)
+	assert.Equal(t, "site", token)
+	assert.Equal(t, "site config", source)
+
+	token, _ = api.ResolveToken(config.Site{Type: "gitlab", Address: "gitlab.com"})
+	assert.Empty(t, token)
+}
+
+func TestResolveTokenEnterprise(t *testing.T) {
+	ghConfig := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(ghConfig, "hosts.yml"), []byte(
+		"github.com:\n    oauth_token: gho_github\n"+
+			"ghe.example.com:\n    oauth_token: gho_enterprise\n"), 0o600))
+	t.Setenv("GH_CONFIG_DIR", ghConfig)
+	for _, name := range append(api.TokenEnvNames, api.EnterpriseTokenEnvNames...) {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GITHUB_TOKEN", "github_env")
+	ghes := config.Site{Type: config.TypeGitHub, Address: "ghe.example.com"}
+
+	token, source := api.ResolveToken(ghes)
+	assert.Equal(t, "gho_enterprise", token)
+	assert.Equal(t, "gh hosts.yml", source)
+
+	token, _ = api.ResolveToken(config.Site{
+		Type: config.TypeGitHub, Address: "other.example.com",
+	})
+	assert.Empty(t, token)
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise_env")
+	token, source = api.ResolveToken(ghes)
+	assert.Equal(t, "enterprise_env", token)
+	assert.Equal(t, "$GH_ENTERPRISE_TOKEN", source)
+}
+
+func TestForSiteSendsTokenOnlyToSite(t *testing.T) {
+	t.Parallel()
+	headers := make(chan string, 2)
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("Authorization")
+	})
+	site := httptest.NewServer(handler)
+	defer site.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+	u, err := url.Parse(site.URL)
+	require.NoError(t, err)
+	ctx := logging.EnsureLogger(context.TestContext(t))
+
+	cl := api.ForSite(ctx, config.Site{
+		Type:    config.TypeGitHub,
+		Address: u.Host,
+		Auth:    &config.Auth{Token: "secret"},
+	}).Client()
+	for _, target := range []string{site.URL, other.URL} {
+		resp, gerr := cl.Get(target)
+		require.NoError(t, gerr)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	assert.Equal(t, "Bearer secret", <-headers)
+	assert.Empty(t, <-headers)
+}
+
+func TestForSiteEnterprise(t *testing.T) {
+	t.Parallel()
+	ctx := logging.EnsureLogger(context.TestContext(t))
+
+	cl := api.ForSite(ctx, config.Site{
+		Type:    config.TypeGitHub,
+		Address: "ghe.example.com",
+	})
+	assert.Equal(t, "https://ghe.example.com/api/v3/", cl.BaseURL.String())
+	assert.Equal(t, "https://ghe.example.com/api/uploads/", cl.UploadURL.String())
+
+	cl = api.ForSite(ctx, config.Site{Type: config.TypeGitHub, Address: "github.com"})
+	assert.Equal(t, "https://api.github.com/", cl.BaseURL.String())
+}
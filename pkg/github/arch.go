@@ -9,24 +9,45 @@ import (
 type Architecture string
 
 const (
-	ArchX86     Architecture = "x86"
-	ArchAMD64   Architecture = "amd64"
-	ArchARM     Architecture = "arm"
-	ArchARM64   Architecture = "arm64"
-	ArchPPC64LE Architecture = "ppc64le"
-	ArchS390X   Architecture = "s390x"
+	ArchX86      Architecture = "x86"
+	ArchAMD64    Architecture = "amd64"
+	ArchARM      Architecture = "arm"
+	ArchARM64    Architecture = "arm64"
+	ArchLoong64  Architecture = "loong64"
+	ArchMIPS     Architecture = "mips"
+	ArchMIPSLE   Architecture = "mipsle"
+	ArchMIPS64   Architecture = "mips64"
+	ArchMIPS64LE Architecture = "mips64le"
+	ArchPPC64    Architecture = "ppc64"
+	ArchPPC64LE  Architecture = "ppc64le"
+	ArchRISCV64  Architecture = "riscv64"
+	ArchS390X    Architecture = "s390x"
+	ArchWasm     Architecture = "wasm"
 )
 
+// architectures are all the known architectures, one for each GOARCH.
+var architectures = []Architecture{ //nolint:gochecknoglobals
+	ArchX86,
+	ArchAMD64,
+	ArchARM,
+	ArchARM64,
+	ArchLoong64,
+	ArchMIPS,
+	ArchMIPSLE,
+	ArchMIPS64,
+	ArchMIPS64LE,
+	ArchPPC64,
+	ArchPPC64LE,
+	ArchRISCV64,
+	ArchS390X,
+	ArchWasm,
+}
+
+// goarch386 is the GOARCH of ArchX86.
+const goarch386 = "386"
+
 func noArchMatches(name string) bool {
-	archs := []Architecture{
-		ArchX86,
-		ArchAMD64,
-		ArchARM,
-		ArchARM64,
-		ArchPPC64LE,
-		ArchS390X,
-	}
-	for _, arch := range archs {
+	for _, arch := range architectures {
 		if arch.Matches(name) {
 			return false
 		}
@@ -38,21 +59,46 @@ func (a Architecture) Matches(name string) bool {
 	return matchWith(name, archMatchers[a])
 }
 
+// GOARCH returns the name of the architecture used by Go, like "386" for
+// ArchX86.
+func (a Architecture) GOARCH() string {
+	if a == ArchX86 {
+		return goarch386
+	}
+	return string(a)
+}
+
+// ArchitectureOf returns the architecture of the given GOARCH, like ArchX86
+// for "386".
+func ArchitectureOf(goarch string) Architecture {
+	if goarch == goarch386 {
+		return ArchX86
+	}
+	return Architecture(goarch)
+}
+
 func CurrentArchitecture() Architecture {
-	return Architecture(runtime.GOARCH)
+	return ArchitectureOf(runtime.GOARCH)
 }
 
+var (
+	mipsLittleEndian = match.Regex("mips(?:-?64)?-?(?:le|el)") //nolint:gochecknoglobals
+	ppcLittleEndian  = match.Regex("p(?:ower)?pc-?64-?le")     //nolint:gochecknoglobals
+)
+
 var archMatchers = map[Architecture]match.Matcher{ //nolint:gochecknoglobals
 	ArchX86: match.Any(
 		match.Every(
 			match.Substr("x86"),
 			match.Not(match.Substr("x86_64")),
+			match.Not(match.Substr("x86-64")),
 		),
 		match.Regex("i?[3-6]86"),
 	),
 	ArchAMD64: match.Any(
 		match.Substr("amd64"),
 		match.Substr("x86_64"),
+		match.Substr("x86-64"),
 		match.Substr("x64"),
 	),
 	ArchARM: match.Any(
@@ -62,7 +108,39 @@ var archMatchers = map[Architecture]match.Matcher{ //nolint:gochecknoglobals
 			match.Not(match.Substr("arm64")),
 		),
 	),
-	ArchARM64:   match.Any(match.Substr("arm64")),
-	ArchPPC64LE: match.Any(match.Regex("ppc-?64-?(?:le)?")),
+	ArchARM64: match.Any(
+		match.Substr("arm64"),
+		match.Substr("aarch64"),
+	),
+	ArchLoong64: match.Any(
+		match.Substr("loong64"),
+		match.Substr("loongarch64"),
+	),
+	ArchMIPS: match.Every(
+		match.Substr("mips"),
+		match.Not(match.Regex("mips-?64")),
+		match.Not(mipsLittleEndian),
+	),
+	ArchMIPSLE: match.Every(
+		mipsLittleEndian,
+		match.Not(match.Regex("mips-?64")),
+	),
+	ArchMIPS64: match.Every(
+		match.Regex("mips-?64"),
+		match.Not(mipsLittleEndian),
+	),
+	ArchMIPS64LE: match.Every(
+		match.Regex("mips-?64"),
+		mipsLittleEndian,
+	),
+	ArchPPC64: match.Every(
+		match.Regex("p(?:ower)?pc-?64"),
+		match.Not(ppcLittleEndian),
+	),
+	ArchPPC64LE: match.Any(ppcLittleEndian),
+	ArchRISCV64: match.Any(match.Regex("riscv-?64")),
 	ArchS390X:   match.Any(match.Substr("s390x")),
+	// The WebAssembly modules are told apart by their extension, as "wasm"
+	// is a common part of the tool names.
+	ArchWasm: match.Any(match.EndsWith(".wasm")),
 }
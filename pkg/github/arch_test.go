@@ -0,0 +1,68 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchitectureMatches(t *testing.T) {
+	t.Parallel()
+	all := []github.Architecture{
+		github.ArchX86, github.ArchAMD64, github.ArchARM, github.ArchARM64,
+		github.ArchLoong64, github.ArchMIPS, github.ArchMIPSLE,
+		github.ArchMIPS64, github.ArchMIPS64LE, github.ArchPPC64,
+		github.ArchPPC64LE, github.ArchRISCV64, github.ArchS390X,
+		github.ArchWasm,
+	}
+	cases := map[string]github.Architecture{
+		"tool-linux-386.tar.gz":                  github.ArchX86,
+		"tool-i686-unknown-linux-musl.tar.gz":    github.ArchX86,
+		"tool_Linux_x86_64.tar.gz":               github.ArchAMD64,
+		"tool-linux-x86-64.tar.gz":               github.ArchAMD64,
+		"tool-linux-armv7.tar.gz":                github.ArchARM,
+		"tool-aarch64-unknown-linux-gnu.tar.gz":  github.ArchARM64,
+		"tool-linux-loong64.tar.gz":              github.ArchLoong64,
+		"tool-loongarch64-unknown-linux-gnu.tgz": github.ArchLoong64,
+		"tool-linux-mips.tar.gz":                 github.ArchMIPS,
+		"tool-linux-mipsle.tar.gz":               github.ArchMIPSLE,
+		"tool-mipsel-unknown-linux-musl.tar.gz":  github.ArchMIPSLE,
+		"tool-linux-mips64.tar.gz":               github.ArchMIPS64,
+		"tool-linux-mips64le.tar.gz":             github.ArchMIPS64LE,
+		"tool-mips64el-unknown-linux-gnu.tgz":    github.ArchMIPS64LE,
+		"tool-linux-ppc64.tar.gz":                github.ArchPPC64,
+		"tool-linux-ppc64le.tar.gz":              github.ArchPPC64LE,
+		"tool-powerpc64le-unknown-linux-gnu.tgz": github.ArchPPC64LE,
+		"tool-riscv64gc-unknown-linux-gnu.tgz":   github.ArchRISCV64,
+		"tool-linux-s390x.tar.gz":                github.ArchS390X,
+		"tool_wasip1_wasm.wasm":                  github.ArchWasm,
+	}
+	for name, want := range cases {
+		name, want := name, want
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			matched := make([]github.Architecture, 0, 1)
+			for _, arch := range all {
+				if arch.Matches(name) {
+					matched = append(matched, arch)
+				}
+			}
+			assert.Equal(t, []github.Architecture{want}, matched)
+		})
+	}
+}
+
+func TestArchitectureOf(t *testing.T) {
+	t.Parallel()
+	goarches := []string{
+		"386", "amd64", "arm", "arm64", "loong64", "mips", "mipsle", "mips64",
+		"mips64le", "ppc64", "ppc64le", "riscv64", "s390x", "wasm",
+	}
+	for _, goarch := range goarches {
+		arch := github.ArchitectureOf(goarch)
+		assert.True(t, arch.Matches("tool-"+goarch) || arch == github.ArchWasm, goarch)
+		assert.Equal(t, goarch, arch.GOARCH())
+	}
+	assert.Equal(t, github.ArchX86, github.ArchitectureOf("386"))
+}
@@ -67,10 +67,11 @@ func (c Checksums) matcher(basename string, arch Architecture, sys OperatingSyst
 		}
 		return match.Any(mm...)
 	}
+	checksums := strings.ToLower(c.ToString())
 	return match.MatcherFn(func(name string) bool {
-		return c.ToString() == name ||
+		return checksums == name ||
 			strings.HasPrefix(name, basename) &&
-				strings.HasSuffix(name, c.ToString()) &&
+				strings.HasSuffix(name, checksums) &&
 				((arch.Matches(name) && sys.Matches(name)) ||
 					(noArchMatches(name) && noOsMatches(name)))
 	})
@@ -82,9 +83,37 @@ type Asset struct {
 	OperatingSystem
 	Release
 	Checksums
+	// Archive, if set, is the name, or a glob, of the asset to pick in place
+	// of the ones matched by the base name, architecture and operating system.
+	Archive string
+	// Rules, if set, customize the matching of the asset names.
+	Rules *Rules `json:"-"`
 }
 
 func (a Asset) Matches(filename string) bool {
+	return a.Explain(filename).Matches
+}
+
+// MatchExplanation tells how the matchers of the asset judged the file name.
+type MatchExplanation struct {
+	Name string `json:"name"`
+	// BaseName is set if the name starts or ends with the base name, or
+	// matches the asset rule, or the archive name if one is given.
+	BaseName bool `json:"basename"`
+	// Coordinates are what is left of the name without the base name. The
+	// architecture and the operating system are matched against them.
+	Coordinates     string `json:"coordinates"`
+	Architecture    bool   `json:"architecture"`
+	OperatingSystem bool   `json:"operatingSystem"`
+	Checksum        bool   `json:"checksum"`
+	// PackageManager is set if the name is a package, like a .deb or a .rpm,
+	// which isn't matched by the Linux systems.
+	PackageManager bool `json:"packageManager"`
+	Matches        bool `json:"matches"`
+}
+
+// Explain tells how the file name is matched, matcher by matcher.
+func (a Asset) Explain(filename string) MatchExplanation {
 	name := strings.ToLower(filename)
 	basename := strings.ToLower(a.BaseName)
 	coords := strings.Trim(
@@ -93,9 +122,28 @@ func (a Asset) Matches(filename string) bool {
 			basename,
 		), "-_",
 	)
-	cm := a.matcher(basename, a.Architecture, a.OperatingSystem)
-	return cm.Matches(name) || ((strings.HasPrefix(name, basename) ||
-		strings.HasSuffix(name, basename)) &&
-		a.Architecture.Matches(coords) &&
-		a.OperatingSystem.Matches(coords))
+	named := strings.HasPrefix(name, basename) || strings.HasSuffix(name, basename)
+	if matched, ok := a.Rules.asset(filename); ok {
+		// The asset rule replaces the base name, so the coordinates can be
+		// anywhere in the name.
+		named, coords = matched, name
+	}
+	e := MatchExplanation{
+		Name:        filename,
+		BaseName:    named,
+		Coordinates: coords,
+		Architecture: a.Architecture.Matches(coords) ||
+			a.Rules.architecture(a.Architecture, coords),
+		OperatingSystem: a.OperatingSystem.Matches(coords) ||
+			a.Rules.operatingSystem(a.OperatingSystem, coords),
+		Checksum:       a.matcher(basename, a.Architecture, a.OperatingSystem).Matches(name),
+		PackageManager: !notPackageManagers.Matches(name),
+	}
+	e.Matches = e.Checksum || (e.BaseName && e.Architecture && e.OperatingSystem)
+	if a.Archive != "" {
+		// The archive name picks the asset by itself.
+		e.BaseName = match.Glob(strings.ToLower(a.Archive)).Matches(name)
+		e.Matches = e.Checksum || e.BaseName
+	}
+	return e
 }
@@ -1,13 +1,10 @@
 package github
 
-const LatestTag = "latest"
+import "github.com/cardil/ghet/pkg/artifact"
 
-type Repository struct {
-	Owner string
-	Repo  string
-}
+const LatestTag = "latest"
 
 type Release struct {
 	Tag string
-	Repository
+	artifact.Repository
 }
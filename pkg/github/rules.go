@@ -0,0 +1,104 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/match"
+)
+
+// ErrUnknownAlias is returned when an alias points to an architecture or an
+// operating system that isn't known.
+var ErrUnknownAlias = errors.New("unknown alias target")
+
+// Rules customize how the assets of a repository are matched, to cope with
+// the odd naming schemes.
+type Rules struct {
+	// Asset, if set, selects the asset names in place of the base name.
+	Asset match.Matcher
+	// Architectures are the extra matchers of the architectures, built from
+	// the aliases.
+	Architectures map[Architecture]match.Matcher
+	// OperatingSystems are the extra matchers of the operating systems,
+	// built from the aliases.
+	OperatingSystems map[OperatingSystem]match.Matcher
+	// Binary is the name of the binary inside the archive.
+	Binary string
+}
+
+// NewRules returns the rules, with the aliases, like "aarch64" to "arm64" or
+// "macos" to "darwin", turned into the matchers of the named tokens. An
+// alias to "linux" applies to both Linux flavors.
+func NewRules(asset match.Matcher, aliases map[string]string, binary string) (*Rules, error) {
+	archTokens := make(map[Architecture][]string)
+	osTokens := make(map[OperatingSystem][]string)
+	for alias, target := range aliases {
+		alias = strings.ToLower(alias)
+		arch := ArchitectureOf(strings.ToLower(target))
+		if _, ok := archMatchers[arch]; ok {
+			archTokens[arch] = append(archTokens[arch], alias)
+			continue
+		}
+		oss := operatingSystemsOf(strings.ToLower(target))
+		if len(oss) == 0 {
+			return nil, errors.WithStack(fmt.Errorf("%w: %s: %q",
+				ErrUnknownAlias, alias, target))
+		}
+		for _, os := range oss {
+			osTokens[os] = append(osTokens[os], alias)
+		}
+	}
+	rules := &Rules{
+		Asset:            asset,
+		Architectures:    make(map[Architecture]match.Matcher, len(archTokens)),
+		OperatingSystems: make(map[OperatingSystem]match.Matcher, len(osTokens)),
+		Binary:           binary,
+	}
+	for arch, tokens := range archTokens {
+		rules.Architectures[arch] = tokenMatcher(tokens)
+	}
+	for os, tokens := range osTokens {
+		rules.OperatingSystems[os] = tokenMatcher(tokens)
+	}
+	return rules, nil
+}
+
+func operatingSystemsOf(target string) []OperatingSystem {
+	if OsFamily(target) == OSFamilyLinux {
+		return []OperatingSystem{OSLinuxGnu, OSLinuxMusl}
+	}
+	if _, ok := osMatchers[OperatingSystem(target)]; ok {
+		return []OperatingSystem{OperatingSystem(target)}
+	}
+	return nil
+}
+
+func tokenMatcher(tokens []string) match.Matcher {
+	return match.MatcherFn(func(name string) bool {
+		return hasAnyToken(strings.ToLower(name), tokens)
+	})
+}
+
+func (r *Rules) asset(name string) (bool, bool) {
+	if r == nil || r.Asset == nil {
+		return false, false
+	}
+	return r.Asset.Matches(name), true
+}
+
+func (r *Rules) architecture(arch Architecture, name string) bool {
+	if r == nil {
+		return false
+	}
+	m, ok := r.Architectures[arch]
+	return ok && m.Matches(name)
+}
+
+func (r *Rules) operatingSystem(os OperatingSystem, name string) bool {
+	if r == nil {
+		return false
+	}
+	m, ok := r.OperatingSystems[os]
+	return ok && m.Matches(name)
+}
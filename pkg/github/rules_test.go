@@ -0,0 +1,36 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/match"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetMatchesWithRules(t *testing.T) {
+	t.Parallel()
+	rules, err := github.NewRules(match.Glob("toolkit-*.tgz"), map[string]string{
+		"m1":    "arm64",
+		"macos": "darwin",
+		"gnu":   "linux",
+	}, "")
+	require.NoError(t, err)
+	asset := github.Asset{
+		FileName:        github.FileName{BaseName: "tool"},
+		Architecture:    github.ArchARM64,
+		OperatingSystem: github.OSDarwin,
+		Rules:           rules,
+	}
+	assert.True(t, asset.Matches("toolkit-macos-m1.tgz"))
+	assert.False(t, asset.Matches("toolkit-macos-m1.zip"))
+	assert.False(t, asset.Matches("toolkit-gnu-m1.tgz"))
+	assert.Greater(t, asset.Score("toolkit-macos-m1.tgz"), asset.Score("toolkit-macos-arm.tgz"))
+
+	asset.OperatingSystem = github.OSLinuxMusl
+	assert.True(t, asset.Matches("toolkit-gnu-m1.tgz"))
+
+	_, err = github.NewRules(nil, map[string]string{"m1": "apple-silicon"}, "")
+	assert.ErrorIs(t, err, github.ErrUnknownAlias)
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Weights of the asset scoring. The penalties outweigh any preference, so a
+// signature or a debug build is never preferred over a regular asset.
+const (
+	exactTokenScore    = 10
+	flavorScore        = 5
+	preferredArchive   = 3
+	acceptableArchive  = 1
+	unwantedAssetScore = -100
+)
+
+// tokenSeparators separate the tokens of the asset names.
+const tokenSeparators = `[-_.]`
+
+var (
+	osTokens = map[OperatingSystem][]string{ //nolint:gochecknoglobals
+		OSDarwin:    {"darwin", "macos", "mac", "osx", "apple"},
+		OSLinuxGnu:  {"linux"},
+		OSLinuxMusl: {"linux"},
+		OSWindows:   {"windows", "win", "win64", "win32"},
+	}
+	archTokens = map[Architecture][]string{ //nolint:gochecknoglobals
+		ArchX86:      {"x86", "386", "i386", "i686"},
+		ArchAMD64:    {"amd64", "x86_64", "x86-64", "x64"},
+		ArchARM:      {"arm", "arm32", "armv6", "armv7", "armhf"},
+		ArchARM64:    {"arm64", "aarch64"},
+		ArchLoong64:  {"loong64", "loongarch64"},
+		ArchMIPS:     {"mips"},
+		ArchMIPSLE:   {"mipsle", "mipsel"},
+		ArchMIPS64:   {"mips64"},
+		ArchMIPS64LE: {"mips64le", "mips64el"},
+		ArchPPC64:    {"ppc64"},
+		ArchPPC64LE:  {"ppc64le", "powerpc64le"},
+		ArchRISCV64:  {"riscv64"},
+		ArchS390X:    {"s390x"},
+		ArchWasm:     {"wasm"},
+	}
+	flavorTokens = map[OperatingSystem][]string{ //nolint:gochecknoglobals
+		OSLinuxGnu:  {"gnu", "glibc"},
+		OSLinuxMusl: {"musl"},
+	}
+	unwantedSuffixes = []string{ //nolint:gochecknoglobals
+		".sbom", ".sbom.json", ".spdx", ".spdx.json", ".cdx.json",
+		".sig", ".asc", ".pem", ".cert", ".crt",
+	}
+	unwantedTokens = []string{"debug", "dbg", "sbom"} //nolint:gochecknoglobals
+)
+
+// Score ranks the file name, as a candidate for the asset. The higher the
+// score, the better the candidate. Exact operating system and architecture
+// tokens, including the aliases of the rules, the Linux flavor, and the
+// archive format preferred on the operating system are rewarded, while
+// signatures, certificates, SBOMs and debug builds are penalized.
+func (a Asset) Score(filename string) int {
+	name := strings.ToLower(filename)
+	score := 0
+	if hasAnyToken(name, osTokens[a.OperatingSystem]) ||
+		a.Rules.operatingSystem(a.OperatingSystem, name) {
+		score += exactTokenScore
+	}
+	if hasAnyToken(name, archTokens[a.Architecture]) ||
+		a.Rules.architecture(a.Architecture, name) {
+		score += exactTokenScore
+	}
+	if hasAnyToken(name, flavorTokens[a.OperatingSystem]) {
+		score += flavorScore
+	}
+	score += a.archiveScore(name)
+	for _, suffix := range unwantedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			score += unwantedAssetScore
+		}
+	}
+	if hasAnyToken(name, unwantedTokens) {
+		score += unwantedAssetScore
+	}
+	return score
+}
+
+// archiveScore prefers a zip on Windows, and a tarball elsewhere.
+func (a Asset) archiveScore(name string) int {
+	zip := strings.HasSuffix(name, ".zip")
+	tarball := strings.HasSuffix(name, ".tar.gz") ||
+		strings.HasSuffix(name, ".tgz") ||
+		strings.HasSuffix(name, ".tar.xz") ||
+		strings.HasSuffix(name, ".txz")
+	switch {
+	case a.OperatingSystem == OSWindows && zip,
+		a.OperatingSystem != OSWindows && tarball:
+		return preferredArchive
+	case zip || tarball:
+		return acceptableArchive
+	}
+	return 0
+}
+
+// hasAnyToken checks if any of the tokens is found in the name, separated
+// from the rest of it.
+func hasAnyToken(name string, tokens []string) bool {
+	for _, token := range tokens {
+		if tokenRegexp(token).MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenRegexp(token string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|` + tokenSeparators + `)` +
+		regexp.QuoteMeta(token) + `(?:$|` + tokenSeparators + `)`)
+}
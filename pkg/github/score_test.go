@@ -0,0 +1,64 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetScore(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name          string
+		os            github.OperatingSystem
+		better, worse string
+	}{{
+		name:   "tarball over zip",
+		os:     github.OSLinuxGnu,
+		better: "tool-linux-amd64.tar.gz",
+		worse:  "tool-linux-amd64.zip",
+	}, {
+		name:   "zip over tarball on windows",
+		os:     github.OSWindows,
+		better: "tool-windows-amd64.zip",
+		worse:  "tool-windows-amd64.tar.gz",
+	}, {
+		name:   "gnu flavor",
+		os:     github.OSLinuxGnu,
+		better: "tool-x86_64-unknown-linux-gnu.tar.gz",
+		worse:  "tool-x86_64-unknown-linux.tar.gz",
+	}, {
+		name:   "exact tokens",
+		os:     github.OSLinuxGnu,
+		better: "tool_linux_x86_64",
+		worse:  "tool_linuxstatic_amd64bit",
+	}, {
+		name:   "signature",
+		os:     github.OSLinuxGnu,
+		better: "tool-linux-amd64",
+		worse:  "tool-linux-amd64.sig",
+	}, {
+		name:   "sbom",
+		os:     github.OSLinuxGnu,
+		better: "tool-linux-amd64.tar.gz",
+		worse:  "tool-linux-amd64.tar.gz.sbom.json",
+	}, {
+		name:   "debug build",
+		os:     github.OSDarwin,
+		better: "tool-darwin-amd64.tar.gz",
+		worse:  "tool-debug-darwin-amd64.tar.gz",
+	}}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			asset := github.Asset{
+				FileName:        github.FileName{BaseName: "tool"},
+				Architecture:    github.ArchAMD64,
+				OperatingSystem: tc.os,
+			}
+			assert.Greater(t, asset.Score(tc.better), asset.Score(tc.worse))
+		})
+	}
+}
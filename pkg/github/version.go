@@ -0,0 +1,49 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+)
+
+// ErrInvalidVersion is returned when the tag or constraint isn't a valid
+// semantic version.
+var ErrInvalidVersion = errors.New("invalid version")
+
+// tagVersionRe captures the version part of tags like "v1.2.3",
+// "cli-v1.2.3" or "knative-v1.9.1".
+var tagVersionRe = regexp.MustCompile(`^(?:.*?[-_/])?v?(\d+(?:\.\d+)*(?:[-+].*)?)$`)
+
+// IsConstraint returns true if the version is a constraint like "^2.40",
+// "~1.2" or ">=1.0,<2", rather than an exact tag.
+func IsConstraint(version string) bool {
+	return strings.ContainsAny(version, "^~<>=!,* ")
+}
+
+// ParseConstraint parses the version constraint.
+func ParseConstraint(version string) (*semver.Constraints, error) {
+	c, err := semver.NewConstraint(version)
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %s: %v",
+			ErrInvalidVersion, version, err))
+	}
+	return c, nil
+}
+
+// ParseTagVersion parses the semantic version of the given tag, ignoring any
+// prefixes.
+func ParseTagVersion(tag string) (*semver.Version, error) {
+	m := tagVersionRe.FindStringSubmatch(tag)
+	if m == nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %s", ErrInvalidVersion, tag))
+	}
+	v, err := semver.NewVersion(m[1])
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %s: %v",
+			ErrInvalidVersion, tag, err))
+	}
+	return v, nil
+}
@@ -0,0 +1,83 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagVersion(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"v1.2.3":              "1.2.3",
+		"1.2.3":               "1.2.3",
+		"cli-v1.2.3":          "1.2.3",
+		"knative-v1.9.1":      "1.9.1",
+		"cli-tools-v1.2.3":    "1.2.3",
+		"v2.41.0-rc.1":        "2.41.0-rc.1",
+		"release/v0.3":        "0.3.0",
+		"lsd-0.23.1":          "0.23.1",
+		"kustomize/v5.4.3":    "5.4.3",
+		"v1.2.3+incompatible": "1.2.3+incompatible",
+	}
+	for tag, want := range cases {
+		tag, want := tag, want
+		t.Run(tag, func(t *testing.T) {
+			t.Parallel()
+			v, err := github.ParseTagVersion(tag)
+			require.NoError(t, err)
+			assert.Equal(t, want, v.String())
+		})
+	}
+	_, err := github.ParseTagVersion("nightly")
+	assert.ErrorIs(t, err, github.ErrInvalidVersion)
+}
+
+func TestConstraints(t *testing.T) {
+	t.Parallel()
+	cases := []testCaseConstraint{{
+		constraint: "^2.40",
+		tag:        "v2.45.0",
+		want:       true,
+	}, {
+		constraint: "^2.40",
+		tag:        "v3.0.0",
+	}, {
+		constraint: "~1.2",
+		tag:        "cli-v1.2.9",
+		want:       true,
+	}, {
+		constraint: "~1.2",
+		tag:        "cli-v1.3.0",
+	}, {
+		constraint: ">=1.0,<2",
+		tag:        "1.99.0",
+		want:       true,
+	}, {
+		constraint: ">=1.0,<2",
+		tag:        "v2.0.0",
+	}}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.constraint+" "+tc.tag, func(t *testing.T) {
+			t.Parallel()
+			assert.True(t, github.IsConstraint(tc.constraint))
+			c, err := github.ParseConstraint(tc.constraint)
+			require.NoError(t, err)
+			v, err := github.ParseTagVersion(tc.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, c.Check(v))
+		})
+	}
+	assert.False(t, github.IsConstraint("v1.2.3"))
+	assert.False(t, github.IsConstraint("knative-v1.9.1"))
+	assert.False(t, github.IsConstraint(github.LatestTag))
+}
+
+type testCaseConstraint struct {
+	constraint string
+	tag        string
+	want       bool
+}
@@ -0,0 +1,13 @@
+package match
+
+import "path"
+
+// Glob matches the names against the shell pattern, like "tool_*_x64.tgz".
+// The pattern should be checked with path.Match beforehand, as the malformed
+// patterns match nothing.
+func Glob(pattern string) Matcher {
+	return MatcherFn(func(name string) bool {
+		ok, err := path.Match(pattern, name)
+		return err == nil && ok
+	})
+}
@@ -0,0 +1,185 @@
+// Package fake provides an in-memory release provider, so the code using the
+// releases can be tested without serving them over HTTP.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/provider"
+)
+
+var _ provider.ReleaseProvider = (*Provider)(nil)
+
+// Release is a release served by the fake provider.
+type Release struct {
+	Tag        string
+	Draft      bool
+	PreRelease bool
+	// Assets are the contents of the assets, keyed by their names.
+	Assets map[string][]byte
+}
+
+// Provider serves the releases added to it. The latest release is the last
+// one added, which isn't a draft or a pre-release. It's safe for concurrent
+// use.
+type Provider struct {
+	mu       sync.RWMutex
+	releases map[artifact.Repository][]provider.Release
+	contents map[string][]byte
+	lastID   int64
+}
+
+// New returns an empty fake provider.
+func New() *Provider {
+	return &Provider{
+		releases: make(map[artifact.Repository][]provider.Release),
+		contents: make(map[string][]byte),
+	}
+}
+
+// Add adds the releases of the repository, in the order they were published.
+func (p *Provider) Add(repo artifact.Repository, releases ...Release) *Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, rel := range releases {
+		names := make([]string, 0, len(rel.Assets))
+		for name := range rel.Assets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		assets := make([]artifact.Asset, 0, len(names))
+		for _, name := range names {
+			p.lastID++
+			content := rel.Assets[name]
+			asset := artifact.Asset{
+				ID:          p.lastID,
+				Name:        name,
+				ContentType: "application/octet-stream",
+				Size:        len(content),
+				URL: fmt.Sprintf("fake://%s/releases/download/%s/%s",
+					repo, url.PathEscape(rel.Tag), url.PathEscape(name)),
+			}
+			p.contents[asset.URL] = content
+			assets = append(assets, asset)
+		}
+		p.releases[repo] = append(p.releases[repo], provider.Release{
+			Tag:        rel.Tag,
+			Draft:      rel.Draft,
+			PreRelease: rel.PreRelease,
+			Assets:     assets,
+		})
+	}
+	return p
+}
+
+func (p *Provider) LatestRelease(
+	_ context.Context, repo artifact.Repository,
+) (*provider.Release, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	rels := p.releases[repo]
+	for i := len(rels) - 1; i >= 0; i-- {
+		if !rels[i].Draft && !rels[i].PreRelease {
+			return clone(rels[i]), nil
+		}
+	}
+	return nil, errors.WithStack(fmt.Errorf("%w: latest release of %s",
+		provider.ErrNotFound, repo))
+}
+
+func (p *Provider) ReleaseByTag(
+	_ context.Context, repo artifact.Repository, tag string,
+) (*provider.Release, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, rel := range p.releases[repo] {
+		if rel.Tag == tag {
+			return clone(rel), nil
+		}
+	}
+	return nil, errors.WithStack(fmt.Errorf("%w: release %s of %s",
+		provider.ErrNotFound, tag, repo))
+}
+
+// ListReleases returns the releases of the repository, the latest first.
+func (p *Provider) ListReleases(
+	_ context.Context, repo artifact.Repository,
+) ([]provider.Release, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	rels, ok := p.releases[repo]
+	if !ok {
+		return nil, errors.WithStack(fmt.Errorf("%w: repository %s",
+			provider.ErrNotFound, repo))
+	}
+	list := make([]provider.Release, 0, len(rels))
+	for i := len(rels) - 1; i >= 0; i-- {
+		list = append(list, *clone(rels[i]))
+	}
+	return list, nil
+}
+
+// OpenAsset serves the content of the asset like an HTTP server would,
+// honoring the range of a resumed download.
+func (p *Provider) OpenAsset(
+	_ context.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	p.mu.RLock()
+	content, ok := p.contents[asset.URL]
+	p.mu.RUnlock()
+	if !ok {
+		return response(http.StatusNotFound, nil, http.Header{}), nil
+	}
+	sum := sha256.Sum256(content)
+	etag := strconv.Quote(hex.EncodeToString(sum[:]))
+	h := http.Header{}
+	h.Set("ETag", etag)
+	h.Set("Content-Type", asset.ContentType)
+	var start int
+	if _, err := fmt.Sscanf(header.Get("Range"), "bytes=%d-", &start); err == nil &&
+		start < len(content) && header.Get("If-Range") == etag {
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d",
+			start, len(content)-1, len(content)))
+		return response(http.StatusPartialContent, content[start:], h), nil
+	}
+	return response(http.StatusOK, content, h), nil
+}
+
+// AssetSize returns the size of the content of the asset.
+func (p *Provider) AssetSize(_ context.Context, asset artifact.Asset) (int, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	content, ok := p.contents[asset.URL]
+	if !ok {
+		return 0, errors.WithStack(fmt.Errorf("%w: asset %s",
+			provider.ErrNotFound, asset.Name))
+	}
+	return len(content), nil
+}
+
+func response(status int, content []byte, header http.Header) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(content)),
+		ContentLength: int64(len(content)),
+	}
+}
+
+func clone(rel provider.Release) *provider.Release {
+	rel.Assets = append([]artifact.Asset(nil), rel.Assets...)
+	return &rel
+}
@@ -0,0 +1,76 @@
+package fake_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+)
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "cardil", Repo: "ghet"}
+	p := fake.New().Add(repo, fake.Release{
+		Tag:    "v0.1.0",
+		Assets: map[string][]byte{"ghet-linux-amd64": []byte("first")},
+	}, fake.Release{
+		Tag:    "v0.2.0",
+		Assets: map[string][]byte{"ghet-linux-amd64": []byte("second")},
+	}, fake.Release{Tag: "v0.3.0-rc.1", PreRelease: true})
+
+	rel, err := p.LatestRelease(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", rel.Tag)
+	require.Len(t, rel.Assets, 1)
+	assert.Equal(t, 6, rel.Assets[0].Size)
+
+	rels, err := p.ListReleases(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, rels, 3)
+	assert.Equal(t, "v0.3.0-rc.1", rels[0].Tag)
+
+	_, err = p.ReleaseByTag(ctx, repo, "v9.9.9")
+	assert.ErrorIs(t, err, provider.ErrNotFound)
+	_, err = p.LatestRelease(ctx, artifact.Repository{Owner: "cardil", Repo: "other"})
+	assert.ErrorIs(t, err, provider.ErrNotFound)
+
+	size, err := p.AssetSize(ctx, rel.Assets[0])
+	require.NoError(t, err)
+	assert.Equal(t, 6, size)
+
+	resp, err := p.OpenAsset(ctx, rel.Assets[0], nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "second", readAll(t, resp))
+
+	header := http.Header{}
+	header.Set("Range", "bytes=3-")
+	header.Set("If-Range", resp.Header.Get("ETag"))
+	resp, err = p.OpenAsset(ctx, rel.Assets[0], header)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "bytes 3-5/6", resp.Header.Get("Content-Range"))
+	assert.Equal(t, "ond", readAll(t, resp))
+
+	missing := rel.Assets[0]
+	missing.URL += ".sig"
+	resp, err = p.OpenAsset(ctx, missing, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
+func readAll(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	bytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(bytes)
+}
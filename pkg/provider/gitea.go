@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+)
+
+// GiteaTokenEnvName is the environment variable the Gitea token is read from,
+// if the site config has none.
+const GiteaTokenEnvName = "GITEA_TOKEN"
+
+// giteaPageLimit is the default maximum of items Gitea returns per page.
+const giteaPageLimit = 50
+
+type gitea struct {
+	base   *url.URL
+	client *http.Client
+}
+
+// NewGitea returns the provider of the Gitea, or Forgejo, API served at the
+// given base URL, like "https://codeberg.org/api/v1/".
+func NewGitea(base *url.URL, client *http.Client) ReleaseProvider {
+	return &gitea{base: base, client: client}
+}
+
+func newGitea(ctx context.Context, site config.Site) ReleaseProvider {
+	host := siteHost(site, "codeberg.org")
+	transport := newTransport(ctx)
+	if token := resolveToken(ctx, site, host, GiteaTokenEnvName); token != "" {
+		transport = newAuthTransport(transport, "Authorization", "token "+token, host)
+	}
+	return NewGitea(
+		&url.URL{Scheme: "https", Host: host, Path: "/api/v1/"},
+		&http.Client{Transport: transport},
+	)
+}
+
+type giteaRelease struct {
+	TagName    string       `json:"tag_name"`
+	Draft      bool         `json:"draft"`
+	PreRelease bool         `json:"prerelease"`
+	Assets     []giteaAsset `json:"assets"`
+}
+
+type giteaAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int    `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (g *gitea) LatestRelease(
+	ctx context.Context, repo artifact.Repository,
+) (*Release, error) {
+	var rel giteaRelease
+	if err := g.get(ctx, g.releasesPath(repo)+"/latest", nil, &rel); err != nil {
+		return nil, err
+	}
+	return rel.release(), nil
+}
+
+func (g *gitea) ReleaseByTag(
+	ctx context.Context, repo artifact.Repository, tag string,
+) (*Release, error) {
+	var rel giteaRelease
+	if err := g.get(ctx, g.releasesPath(repo)+"/tags/"+url.PathEscape(tag), nil, &rel); err != nil {
+		return nil, err
+	}
+	return rel.release(), nil
+}
+
+// ListReleases pages through the releases, following the next page link, or
+// the total count. The servers may cap the page size below the requested
+// limit, so a page that isn't full is the last one only if the server reports
+// neither.
+func (g *gitea) ListReleases(
+	ctx context.Context, repo artifact.Repository,
+) ([]Release, error) {
+	releases := make([]Release, 0, giteaPageLimit)
+	u, err := g.base.Parse(g.releasesPath(repo))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	query := url.Values{"limit": {strconv.Itoa(giteaPageLimit)}, "page": {"1"}}
+	u.RawQuery = query.Encode()
+	for page := 1; ; page++ {
+		var rels []giteaRelease
+		resp, err := getJSON(ctx, g.client, u, &rels)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			releases = append(releases, *rel.release())
+		}
+		if next := nextLink(resp.Header); next != "" {
+			if u, err = u.Parse(next); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			continue
+		}
+		if !morePages(resp.Header, len(rels), len(releases)) {
+			return releases, nil
+		}
+		query.Set("page", strconv.Itoa(page+1))
+		u.RawQuery = query.Encode()
+	}
+}
+
+// morePages tells whether there are more releases to list, by the total count
+// the server reports, or by the page being full.
+func morePages(header http.Header, onPage, listed int) bool {
+	if onPage == 0 {
+		return false
+	}
+	if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+		return listed < total
+	}
+	return onPage == giteaPageLimit
+}
+
+func (g *gitea) OpenAsset(
+	ctx context.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	return openAsset(ctx, g.client, asset, header)
+}
+
+func (g *gitea) AssetSize(ctx context.Context, asset artifact.Asset) (int, error) {
+	return assetSize(ctx, g.client, asset)
+}
+
+func (g *gitea) releasesPath(repo artifact.Repository) string {
+	return "repos/" + url.PathEscape(repo.Owner) + "/" +
+		url.PathEscape(repo.Repo) + "/releases"
+}
+
+func (g *gitea) get(
+	ctx context.Context, path string, query url.Values, v any,
+) error {
+	u, err := g.base.Parse(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	u.RawQuery = query.Encode()
+	_, err = getJSON(ctx, g.client, u, v)
+	return err
+}
+
+func (r giteaRelease) release() *Release {
+	assets := make([]artifact.Asset, 0, len(r.Assets))
+	for _, asset := range r.Assets {
+		assets = append(assets, artifact.Asset{
+			ID:          asset.ID,
+			Name:        asset.Name,
+			ContentType: binaryContentType,
+			Size:        asset.Size,
+			URL:         asset.BrowserDownloadURL,
+		})
+	}
+	return &Release{
+		Tag:        r.TagName,
+		Draft:      r.Draft,
+		PreRelease: r.PreRelease,
+		Assets:     assets,
+	}
+}
+
+// nextLink returns the URL of the next page, from the Link header.
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			target, params, found := strings.Cut(link, ";")
+			if !found {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				if strings.TrimSpace(param) == `rel="next"` {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
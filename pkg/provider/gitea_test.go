@@ -0,0 +1,127 @@
+package provider_test
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+)
+
+//go:embed testdata/*
+var testdata embed.FS
+
+func TestGitea(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "mergiraf", Repo: "mergiraf"}
+	p := giteaServer(t)
+
+	rel, err := p.LatestRelease(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.4.0", rel.Tag)
+	assert.False(t, rel.PreRelease)
+	require.Len(t, rel.Assets, 3)
+	assert.Equal(t, artifact.Asset{
+		ID:          571246,
+		Name:        "mergiraf_x86_64-unknown-linux-gnu.tar.gz",
+		ContentType: "application/octet-stream",
+		Size:        3_312_077,
+		URL:         "https://codeberg.org/mergiraf/mergiraf/releases/download/v0.4.0/mergiraf_x86_64-unknown-linux-gnu.tar.gz",
+	}, rel.Assets[1])
+
+	rel, err = p.ReleaseByTag(ctx, repo, "v0.4.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.4.0", rel.Tag)
+
+	rels, err := p.ListReleases(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, rels, 3)
+	assert.Equal(t, "v0.5.0-rc.1", rels[0].Tag)
+	assert.True(t, rels[0].PreRelease)
+
+	_, err = p.ReleaseByTag(ctx, repo, "v0.0.1")
+	assert.ErrorIs(t, err, provider.ErrNotFound)
+}
+
+func TestGiteaListReleasesPages(t *testing.T) {
+	t.Parallel()
+	const releases = "/api/v1/repos/mergiraf/mergiraf/releases"
+	bytes, err := testdata.ReadFile("testdata/GET-mergiraf-mergiraf-releases.json")
+	require.NoError(t, err)
+	var all []json.RawMessage
+	require.NoError(t, json.Unmarshal(bytes, &all))
+	for name, paging := range map[string]func(w http.ResponseWriter, r *http.Request, page int){
+		"link": func(w http.ResponseWriter, r *http.Request, page int) {
+			if page == 1 {
+				w.Header().Set("Link", fmt.Sprintf(
+					`<http://%s%s?limit=50&page=2>; rel="next",<http://%s%s?limit=50&page=2>; rel="last"`,
+					r.Host, releases, r.Host, releases))
+			}
+		},
+		"total count": func(w http.ResponseWriter, _ *http.Request, _ int) {
+			w.Header().Set("X-Total-Count", strconv.Itoa(len(all)))
+		},
+	} {
+		paging := paging
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.TestContext(t)
+			mux := http.NewServeMux()
+			mux.HandleFunc(releases, func(w http.ResponseWriter, r *http.Request) {
+				// The server caps the page size at 2 items.
+				page, err := strconv.Atoi(r.URL.Query().Get("page"))
+				require.NoError(t, err)
+				from := min((page-1)*2, len(all))
+				paging(w, r, page)
+				require.NoError(t, json.NewEncoder(w).Encode(all[from:min(from+2, len(all))]))
+			})
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+			base, err := url.Parse(server.URL + "/api/v1/")
+			require.NoError(t, err)
+			p := provider.NewGitea(base, server.Client())
+
+			rels, err := p.ListReleases(ctx, artifact.Repository{Owner: "mergiraf", Repo: "mergiraf"})
+			require.NoError(t, err)
+			require.Len(t, rels, len(all))
+			assert.Equal(t, "v0.5.0-rc.1", rels[0].Tag)
+		})
+	}
+}
+
+func giteaServer(t *testing.T) provider.ReleaseProvider {
+	t.Helper()
+	const releases = "/api/v1/repos/mergiraf/mergiraf/releases"
+	serve := func(file string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			bytes, err := testdata.ReadFile("testdata/" + file)
+			require.NoError(t, err)
+			_, _ = w.Write(bytes)
+		}
+	}
+	latest := serve("GET-mergiraf-mergiraf-releases-latest.json")
+	list := serve("GET-mergiraf-mergiraf-releases.json")
+	mux := http.NewServeMux()
+	mux.Handle(releases+"/latest", latest)
+	mux.Handle(releases+"/tags/v0.4.0", latest)
+	mux.HandleFunc(releases, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+		list(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	base, err := url.Parse(server.URL + "/api/v1/")
+	require.NoError(t, err)
+	return provider.NewGitea(base, server.Client())
+}
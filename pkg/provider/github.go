@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	pkggithub "github.com/cardil/ghet/pkg/github"
+	githubapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/google/go-github/v48/github"
+)
+
+const releasesPerPage = 100
+
+type gitHub struct {
+	client *github.Client
+	site   config.Site
+}
+
+// NewGitHub returns the provider of the releases of the given GitHub site,
+// fetched with the given client.
+func NewGitHub(client *github.Client, site config.Site) ReleaseProvider {
+	return &gitHub{client: client, site: site}
+}
+
+func newGitHub(ctx context.Context, site config.Site) ReleaseProvider {
+	return NewGitHub(githubapi.ForSite(ctx, site), site)
+}
+
+func (g *gitHub) LatestRelease(
+	ctx context.Context, repo artifact.Repository,
+) (*Release, error) {
+	rr, _, err := g.client.Repositories.GetLatestRelease(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return nil, gitHubError(err)
+	}
+	return g.release(rr), nil
+}
+
+func (g *gitHub) ReleaseByTag(
+	ctx context.Context, repo artifact.Repository, tag string,
+) (*Release, error) {
+	rr, _, err := g.client.Repositories.GetReleaseByTag(ctx, repo.Owner, repo.Repo, tag)
+	if err != nil {
+		return nil, gitHubError(err)
+	}
+	return g.release(rr), nil
+}
+
+func (g *gitHub) ListReleases(
+	ctx context.Context, repo artifact.Repository,
+) ([]Release, error) {
+	releases := make([]Release, 0, releasesPerPage)
+	opts := &github.ListOptions{PerPage: releasesPerPage}
+	for {
+		rrs, r, err := g.client.Repositories.ListReleases(ctx, repo.Owner, repo.Repo, opts)
+		if err != nil {
+			return nil, gitHubError(err)
+		}
+		for _, rr := range rrs {
+			releases = append(releases, *g.release(rr))
+		}
+		if r.NextPage == 0 {
+			return releases, nil
+		}
+		opts.Page = r.NextPage
+	}
+}
+
+func (g *gitHub) OpenAsset(
+	ctx context.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	return openAsset(ctx, g.client.Client(), asset, header)
+}
+
+func (g *gitHub) AssetSize(ctx context.Context, asset artifact.Asset) (int, error) {
+	return assetSize(ctx, g.client.Client(), asset)
+}
+
+func (g *gitHub) release(rr *github.RepositoryRelease) *Release {
+	assets := make([]artifact.Asset, 0, len(rr.Assets))
+	for _, asset := range rr.Assets {
+		assets = append(assets, artifact.Asset{
+			ID:          asset.GetID(),
+			Name:        asset.GetName(),
+			ContentType: asset.GetContentType(),
+			Size:        asset.GetSize(),
+			URL:         g.assetURL(asset),
+		})
+	}
+	return &Release{
+		Tag:        rr.GetTagName(),
+		Draft:      rr.GetDraft(),
+		PreRelease: rr.GetPrerelease(),
+		Assets:     assets,
+	}
+}
+
+// gitHubTags discovers the releases from the tags of the GitHub repository,
+// so the projects that push the tags without publishing the GitHub releases
+// are found too. The releases have no assets.
+type gitHubTags struct {
+	*gitHub
+}
+
+func newGitHubTags(ctx context.Context, site config.Site) ReleaseProvider {
+	return &gitHubTags{gitHub: &gitHub{client: githubapi.ForSite(ctx, site), site: site}}
+}
+
+// LatestRelease returns the release of the highest semantic version tag,
+// which isn't a pre-release.
+func (g *gitHubTags) LatestRelease(
+	ctx context.Context, repo artifact.Repository,
+) (*Release, error) {
+	rels, err := g.ListReleases(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		latest  *Release
+		version *semver.Version
+	)
+	for i, rel := range rels {
+		v, verr := pkggithub.ParseTagVersion(rel.Tag)
+		if verr != nil || rel.PreRelease {
+			continue
+		}
+		if version == nil || v.GreaterThan(version) {
+			latest, version = &rels[i], v
+		}
+	}
+	if latest == nil {
+		return nil, errors.WithStack(fmt.Errorf(
+			"%w: no version tags in %s", ErrNotFound, repo))
+	}
+	return latest, nil
+}
+
+func (g *gitHubTags) ReleaseByTag(
+	ctx context.Context, repo artifact.Repository, tag string,
+) (*Release, error) {
+	if _, _, err := g.client.Git.GetRef(ctx, repo.Owner, repo.Repo, "tags/"+tag); err != nil {
+		return nil, gitHubError(err)
+	}
+	return tagRelease(tag), nil
+}
+
+func (g *gitHubTags) ListReleases(
+	ctx context.Context, repo artifact.Repository,
+) ([]Release, error) {
+	releases := make([]Release, 0, releasesPerPage)
+	opts := &github.ListOptions{PerPage: releasesPerPage}
+	for {
+		tags, r, err := g.client.Repositories.ListTags(ctx, repo.Owner, repo.Repo, opts)
+		if err != nil {
+			return nil, gitHubError(err)
+		}
+		for _, tag := range tags {
+			releases = append(releases, *tagRelease(tag.GetName()))
+		}
+		if r.NextPage == 0 {
+			return releases, nil
+		}
+		opts.Page = r.NextPage
+	}
+}
+
+// tagRelease returns the release of the tag. The tags of the pre-release
+// versions are marked as such.
+func tagRelease(tag string) *Release {
+	v, err := pkggithub.ParseTagVersion(tag)
+	return &Release{
+		Tag:        tag,
+		PreRelease: err == nil && v.Prerelease() != "",
+	}
+}
+
+// assetURL returns the URL the asset is downloaded from. The browser URLs of
+// private releases don't accept the token, so the API endpoint is used
+// instead, if a token is available.
+func (g *gitHub) assetURL(asset *github.ReleaseAsset) string {
+	if asset.GetURL() != "" {
+		if token, _ := githubapi.ResolveToken(g.site); token != "" {
+			return asset.GetURL()
+		}
+	}
+	return asset.GetBrowserDownloadURL()
+}
+
+// gitHubError marks the errors of missing releases, keeping the original
+// error of the API.
+func gitHubError(err error) error {
+	var rerr *github.ErrorResponse
+	if errors.As(err, &rerr) && rerr.Response != nil &&
+		rerr.Response.StatusCode == http.StatusNotFound {
+		return errors.WithStack(fmt.Errorf("%w: %w", ErrNotFound, err))
+	}
+	return errors.WithStack(err)
+}
+
+// newTransport retries the failed requests, the same way for all the sites.
+func newTransport(ctx context.Context) http.RoundTripper {
+	return githubapi.NewTransport(ctx)
+}
+
+// newAuthTransport sets the header only on the requests made to the hosts,
+// like the GitHub client does.
+func newAuthTransport(
+	base http.RoundTripper, header, value string, hosts ...string,
+) http.RoundTripper {
+	return githubapi.NewAuthTransport(base, header, value, hosts...)
+}
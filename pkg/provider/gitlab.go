@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+)
+
+// GitLabTokenEnvName is the environment variable the GitLab token is read
+// from, if the site config has none.
+const GitLabTokenEnvName = "GITLAB_TOKEN"
+
+type gitLab struct {
+	base   *url.URL
+	client *http.Client
+}
+
+// NewGitLab returns the provider of the GitLab API served at the given base
+// URL, like "https://gitlab.com/api/v4/".
+func NewGitLab(base *url.URL, client *http.Client) ReleaseProvider {
+	return &gitLab{base: base, client: client}
+}
+
+func newGitLab(ctx context.Context, site config.Site) ReleaseProvider {
+	host := siteHost(site, "gitlab.com")
+	transport := newTransport(ctx)
+	if token := resolveToken(ctx, site, host, GitLabTokenEnvName); token != "" {
+		transport = newAuthTransport(transport, "PRIVATE-TOKEN", token, host)
+	}
+	return NewGitLab(
+		&url.URL{Scheme: "https", Host: host, Path: "/api/v4/"},
+		&http.Client{Transport: transport},
+	)
+}
+
+type gitLabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitLabLink `json:"links"`
+	} `json:"assets"`
+}
+
+type gitLabLink struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+func (g *gitLab) LatestRelease(
+	ctx context.Context, repo artifact.Repository,
+) (*Release, error) {
+	var rel gitLabRelease
+	if _, err := g.get(ctx, g.releasesPath(repo)+"/permalink/latest", nil, &rel); err != nil {
+		return nil, err
+	}
+	return rel.release(), nil
+}
+
+func (g *gitLab) ReleaseByTag(
+	ctx context.Context, repo artifact.Repository, tag string,
+) (*Release, error) {
+	var rel gitLabRelease
+	if _, err := g.get(ctx, g.releasesPath(repo)+"/"+url.PathEscape(tag), nil, &rel); err != nil {
+		return nil, err
+	}
+	return rel.release(), nil
+}
+
+func (g *gitLab) ListReleases(
+	ctx context.Context, repo artifact.Repository,
+) ([]Release, error) {
+	releases := make([]Release, 0, releasesPerPage)
+	query := url.Values{"per_page": {strconv.Itoa(releasesPerPage)}}
+	for {
+		var rels []gitLabRelease
+		resp, err := g.get(ctx, g.releasesPath(repo), query, &rels)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			releases = append(releases, *rel.release())
+		}
+		next := resp.Header.Get("X-Next-Page")
+		if next == "" {
+			return releases, nil
+		}
+		query.Set("page", next)
+	}
+}
+
+func (g *gitLab) OpenAsset(
+	ctx context.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	return openAsset(ctx, g.client, asset, header)
+}
+
+func (g *gitLab) AssetSize(ctx context.Context, asset artifact.Asset) (int, error) {
+	return assetSize(ctx, g.client, asset)
+}
+
+// releasesPath returns the path of the project releases. The project is
+// identified by its URL-encoded path, which may include subgroups.
+func (g *gitLab) releasesPath(repo artifact.Repository) string {
+	return "projects/" + url.PathEscape(repo.String()) + "/releases"
+}
+
+func (g *gitLab) get(
+	ctx context.Context, path string, query url.Values, v any,
+) (*http.Response, error) {
+	u, err := g.base.Parse(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	u.RawQuery = query.Encode()
+	return getJSON(ctx, g.client, u, v)
+}
+
+// release converts the GitLab release. The links don't carry the sizes of
+// the assets, so they are left unknown.
+func (r gitLabRelease) release() *Release {
+	assets := make([]artifact.Asset, 0, len(r.Assets.Links))
+	for _, link := range r.Assets.Links {
+		u := link.DirectAssetURL
+		if u == "" {
+			u = link.URL
+		}
+		assets = append(assets, artifact.Asset{
+			ID:          link.ID,
+			Name:        link.Name,
+			ContentType: binaryContentType,
+			URL:         u,
+		})
+	}
+	return &Release{
+		Tag:        r.TagName,
+		PreRelease: r.UpcomingRelease,
+		Assets:     assets,
+	}
+}
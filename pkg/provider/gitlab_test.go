@@ -0,0 +1,112 @@
+package provider_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+)
+
+func TestGitLab(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "group", Repo: "sub/tool"}
+	p := gitLabServer(t)
+
+	rel, err := p.LatestRelease(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, &provider.Release{
+		Tag: "v1.1.0",
+		Assets: []artifact.Asset{{
+			ID:          11,
+			Name:        "tool-linux-amd64",
+			ContentType: "application/octet-stream",
+			URL:         "https://gitlab.example.com/group/sub/tool/-/releases/v1.1.0/downloads/tool-linux-amd64",
+		}, {
+			ID:          12,
+			Name:        "checksums.txt",
+			ContentType: "application/octet-stream",
+			URL:         "https://gitlab.example.com/uploads/checksums.txt",
+		}},
+	}, rel)
+
+	rel, err = p.ReleaseByTag(ctx, repo, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", rel.Tag)
+
+	rels, err := p.ListReleases(ctx, repo)
+	require.NoError(t, err)
+	tags := make([]string, 0, len(rels))
+	for _, r := range rels {
+		tags = append(tags, r.Tag)
+	}
+	assert.Equal(t, []string{"v1.2.0-rc.1", "v1.1.0", "v1.0.0"}, tags)
+	assert.True(t, rels[0].PreRelease)
+
+	_, err = p.ReleaseByTag(ctx, repo, "v0.0.1")
+	assert.ErrorIs(t, err, provider.ErrNotFound)
+}
+
+func TestForSite(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+
+	_, err := provider.ForSite(ctx, config.Site{Type: "svn", Address: "svn.example.com"}, github.Asset{})
+	assert.ErrorIs(t, err, provider.ErrUnsupportedSite)
+
+	p, err := provider.ForSite(ctx, config.Site{Type: config.TypeGitLab}, github.Asset{})
+	require.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+const (
+	gitLabReleasesPath = "/api/v4/projects/group%2Fsub%2Ftool/releases"
+	gitLabRelease      = `{"tag_name": %q, "upcoming_release": %t, "assets": {
+		"links": [%s], "sources": [{"format": "zip", "url": "https://gitlab.example.com/source.zip"}]
+	}}`
+	gitLabLinks = `{
+		"id": 11, "name": "tool-linux-amd64",
+		"url": "https://gitlab.example.com/uploads/tool-linux-amd64",
+		"direct_asset_url": "https://gitlab.example.com/group/sub/tool/-/releases/v1.1.0/downloads/tool-linux-amd64"
+	}, {
+		"id": 12, "name": "checksums.txt",
+		"url": "https://gitlab.example.com/uploads/checksums.txt"
+	}`
+)
+
+func gitLabServer(t *testing.T) provider.ReleaseProvider {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case gitLabReleasesPath + "/permalink/latest":
+			_, _ = fmt.Fprintf(w, gitLabRelease, "v1.1.0", false, gitLabLinks)
+		case gitLabReleasesPath + "/v1.0.0":
+			_, _ = fmt.Fprintf(w, gitLabRelease, "v1.0.0", false, "")
+		case gitLabReleasesPath:
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("X-Next-Page", "2")
+				_, _ = fmt.Fprintf(w, "["+gitLabRelease+","+gitLabRelease+"]",
+					"v1.2.0-rc.1", true, "", "v1.1.0", false, gitLabLinks)
+				return
+			}
+			_, _ = fmt.Fprintf(w, "["+gitLabRelease+"]", "v1.0.0", false, "")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	base, err := url.Parse(server.URL + "/api/v4/")
+	require.NoError(t, err)
+	return provider.NewGitLab(base, server.Client())
+}
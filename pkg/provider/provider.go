@@ -0,0 +1,82 @@
+// Package provider fetches the releases from the sites of different types,
+// so they can be downloaded the same way.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github"
+)
+
+// ErrUnsupportedSite is returned when the site type has no provider.
+var ErrUnsupportedSite = errors.New("unsupported site type")
+
+// ErrUnexpectedResponse is returned when the site responds with an error.
+var ErrUnexpectedResponse = errors.New("unexpected response")
+
+// ErrNotFound is returned when the repository or the release doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Release is a release of a repository, along with its assets.
+type Release struct {
+	Tag        string
+	Draft      bool
+	PreRelease bool
+	Assets     []artifact.Asset
+	// Targeted is set when the assets are already picked for the target
+	// platform, so they shouldn't be matched by their names.
+	Targeted bool
+}
+
+// ReleaseProvider fetches the releases of the repositories hosted on a site.
+type ReleaseProvider interface {
+	// LatestRelease returns the latest release, which isn't a pre-release.
+	LatestRelease(ctx context.Context, repo artifact.Repository) (*Release, error)
+	// ReleaseByTag returns the release of the given tag.
+	ReleaseByTag(ctx context.Context, repo artifact.Repository, tag string) (*Release, error)
+	// ListReleases returns all the releases of the repository.
+	ListReleases(ctx context.Context, repo artifact.Repository) ([]Release, error)
+	// OpenAsset opens the content of the asset. The header is sent along with
+	// the request, like the range of a resumed download. The response is
+	// returned regardless of its status, and the caller closes its body.
+	OpenAsset(ctx context.Context, asset artifact.Asset, header http.Header) (*http.Response, error)
+	// AssetSize asks for the size of the asset, without downloading it. Zero
+	// is returned, if the site doesn't tell the size.
+	AssetSize(ctx context.Context, asset artifact.Asset) (int, error)
+}
+
+type providerKey struct{}
+
+// ForSite returns the provider for the type of the given site. The target is
+// the asset to be downloaded, as the assets of some sites depend on it. The
+// provider set in the context takes precedence.
+func ForSite(
+	ctx context.Context, site config.Site, target github.Asset,
+) (ReleaseProvider, error) {
+	if p, ok := ctx.Value(providerKey{}).(ReleaseProvider); ok {
+		return p, nil
+	}
+	switch site.Type {
+	case "", config.TypeGitHub:
+		return newGitHub(ctx, site), nil
+	case config.TypeGitLab:
+		return newGitLab(ctx, site), nil
+	case config.TypeGitea:
+		return newGitea(ctx, site), nil
+	case config.TypeURL:
+		return newURLTemplate(ctx, site, target)
+	default:
+		return nil, errors.WithStack(fmt.Errorf("%w: %q",
+			ErrUnsupportedSite, site.Type))
+	}
+}
+
+// WithContext sets the provider to be used regardless of the site.
+func WithContext(ctx context.Context, p ReleaseProvider) context.Context {
+	return context.WithValue(ctx, providerKey{}, p)
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"knative.dev/client/pkg/output/logging"
+)
+
+// binaryContentType is set on the assets of the sites that don't report
+// their content types, so the downloaded binaries are made executable.
+const binaryContentType = "application/octet-stream"
+
+// siteHost returns the address of the site, or the given default one.
+func siteHost(site config.Site, def string) string {
+	if site.Address == "" {
+		return def
+	}
+	return site.Address
+}
+
+// resolveToken returns the token of the site config, or the one set in the
+// given environment variable. Only the source of the token is logged.
+func resolveToken(ctx context.Context, site config.Site, host, envName string) string {
+	token, source := site.EffectiveToken(), "site config"
+	if token == "" {
+		token, source = os.Getenv(envName), "$"+envName
+	}
+	if token == "" {
+		source = ""
+	}
+	logging.LoggerFrom(logging.EnsureLogger(ctx)).WithFields(logging.Fields{
+		"site":        host,
+		"tokenSource": source,
+	}).Debug("Resolved API token")
+	return token
+}
+
+// getJSON fetches the given URL, and decodes the JSON response into v.
+func getJSON(
+	ctx context.Context, client *http.Client, u *url.URL, v any,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.WithStack(fmt.Errorf("%w: GET %s: %s",
+			ErrNotFound, u.Redacted(), resp.Status))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.WithStack(fmt.Errorf("%w: GET %s: %s",
+			ErrUnexpectedResponse, u.Redacted(), resp.Status))
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: GET %s: %v",
+			ErrUnexpectedResponse, u.Redacted(), err))
+	}
+	return resp, nil
+}
+
+// openAsset requests the content of the asset with the given client.
+func openAsset(
+	ctx context.Context, client *http.Client,
+	asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	// The API endpoints of assets serve the content only if asked for it.
+	req.Header.Set("Accept", "application/octet-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return resp, nil
+}
+
+// assetSize asks for the size of the asset with a HEAD request, so the asset
+// isn't downloaded.
+func assetSize(
+	ctx context.Context, client *http.Client, asset artifact.Asset,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, asset.URL, nil)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, errors.WithStack(fmt.Errorf("%w: HEAD %s: %s",
+			ErrUnexpectedResponse, asset.Name, resp.Status))
+	}
+	if resp.ContentLength < 0 {
+		return 0, nil
+	}
+	return int(resp.ContentLength), nil
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github"
+)
+
+// ErrInvalidTemplate is returned when the URL template can't be parsed or
+// rendered.
+var ErrInvalidTemplate = errors.New("invalid URL template")
+
+// checksumsName is the name given to the checksum asset, if its URL doesn't
+// end with a name that is recognized as a checksum.
+const checksumsName = "checksums.txt"
+
+// TemplateData is given to the URL templates.
+type TemplateData struct {
+	// Tag is the tag of the release, like "v1.30.2".
+	Tag string
+	// Version is the version of the release, without any tag prefix, like
+	// "1.30.2".
+	Version string
+	Owner   string
+	Repo    string
+	// Name is the base name of the binary.
+	Name string
+	// OS is the operating system family, like "linux", "darwin" or "windows".
+	OS string
+	// Arch is the architecture, as named by Go, like "amd64" or "arm64".
+	Arch string
+	// Ext is the extension of the executables, ".exe" on Windows.
+	Ext string
+}
+
+type urlTemplate struct {
+	releases         ReleaseProvider
+	client           *http.Client
+	url, checksumURL *template.Template
+	target           github.Asset
+}
+
+// NewURLTemplate returns the provider of the assets served at the URLs
+// rendered from the template for the given target. The releases are
+// discovered by the given provider.
+func NewURLTemplate(
+	releases ReleaseProvider, client *http.Client,
+	tpl config.Template, target github.Asset,
+) (ReleaseProvider, error) {
+	u := &urlTemplate{releases: releases, client: client, target: target}
+	var err error
+	if u.url, err = parseTemplate("url", tpl.URL); err != nil {
+		return nil, err
+	}
+	if tpl.ChecksumURL != "" {
+		if u.checksumURL, err = parseTemplate("checksumUrl", tpl.ChecksumURL); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func newURLTemplate(
+	ctx context.Context, site config.Site, target github.Asset,
+) (ReleaseProvider, error) {
+	if site.Template == nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: site %q has no template",
+			ErrInvalidTemplate, site.Address))
+	}
+	gh := config.FromContext(ctx).Site("github.com")
+	return NewURLTemplate(newGitHubTags(ctx, gh),
+		&http.Client{Transport: newTransport(ctx)},
+		*site.Template, target)
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %v", ErrInvalidTemplate, err))
+	}
+	return tpl, nil
+}
+
+func (u *urlTemplate) LatestRelease(
+	ctx context.Context, repo artifact.Repository,
+) (*Release, error) {
+	rel, err := u.releases.LatestRelease(ctx, repo)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return u.render(repo, *rel)
+}
+
+func (u *urlTemplate) ReleaseByTag(
+	ctx context.Context, repo artifact.Repository, tag string,
+) (*Release, error) {
+	rel, err := u.releases.ReleaseByTag(ctx, repo, tag)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return u.render(repo, *rel)
+}
+
+func (u *urlTemplate) ListReleases(
+	ctx context.Context, repo artifact.Repository,
+) ([]Release, error) {
+	rels, err := u.releases.ListReleases(ctx, repo)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	for i := range rels {
+		var rel *Release
+		if rel, err = u.render(repo, rels[i]); err != nil {
+			return nil, err
+		}
+		rels[i] = *rel
+	}
+	return rels, nil
+}
+
+func (u *urlTemplate) OpenAsset(
+	ctx context.Context, asset artifact.Asset, header http.Header,
+) (*http.Response, error) {
+	return openAsset(ctx, u.client, asset, header)
+}
+
+func (u *urlTemplate) AssetSize(ctx context.Context, asset artifact.Asset) (int, error) {
+	return assetSize(ctx, u.client, asset)
+}
+
+// render replaces the assets of the release with the ones served at the
+// rendered URLs. The sizes of the assets aren't known upfront.
+func (u *urlTemplate) render(repo artifact.Repository, rel Release) (*Release, error) {
+	data := u.data(repo, rel.Tag)
+	assetURL, err := execute(u.url, data)
+	if err != nil {
+		return nil, err
+	}
+	rel.Assets = []artifact.Asset{{
+		Name:        nameOf(assetURL),
+		ContentType: binaryContentType,
+		URL:         assetURL,
+	}}
+	if u.checksumURL != nil {
+		var checksumURL string
+		if checksumURL, err = execute(u.checksumURL, data); err != nil {
+			return nil, err
+		}
+		checksum := artifact.Asset{Name: nameOf(checksumURL), URL: checksumURL}
+		if len(artifact.CreateIndex([]artifact.Asset{checksum}).Checksums) == 0 {
+			checksum.Name = checksumsName
+		}
+		rel.Assets = append(rel.Assets, checksum)
+	}
+	rel.Targeted = true
+	return &rel, nil
+}
+
+func (u *urlTemplate) data(repo artifact.Repository, tag string) TemplateData {
+	version := strings.TrimPrefix(tag, "v")
+	if v, err := github.ParseTagVersion(tag); err == nil {
+		version = v.Original()
+	}
+	os := string(u.target.OperatingSystem)
+	if strings.HasPrefix(os, string(github.OSFamilyLinux)) {
+		os = string(github.OSFamilyLinux)
+	}
+	ext := ""
+	if u.target.OperatingSystem == github.OSWindows {
+		ext = ".exe"
+	}
+	return TemplateData{
+		Tag:     tag,
+		Version: version,
+		Owner:   repo.Owner,
+		Repo:    repo.Repo,
+		Name:    u.target.BaseName,
+		OS:      os,
+		Arch:    u.target.Architecture.GOARCH(),
+		Ext:     ext,
+	}
+}
+
+func execute(tpl *template.Template, data TemplateData) (string, error) {
+	var sb strings.Builder
+	if err := tpl.Execute(&sb, data); err != nil {
+		return "", errors.WithStack(fmt.Errorf("%w: %v", ErrInvalidTemplate, err))
+	}
+	return sb.String(), nil
+}
+
+// nameOf returns the file name the URL points to.
+func nameOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+	return path.Base(rawURL)
+}
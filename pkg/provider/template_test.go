@@ -0,0 +1,109 @@
+package provider_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cardil/ghet/pkg/artifact"
+	"github.com/cardil/ghet/pkg/config"
+	"github.com/cardil/ghet/pkg/github"
+	ghapi "github.com/cardil/ghet/pkg/github/api"
+	"github.com/cardil/ghet/pkg/provider"
+	"github.com/cardil/ghet/pkg/provider/fake"
+	gh "github.com/google/go-github/v48/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/client/pkg/context"
+)
+
+func TestURLTemplate(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "hashicorp", Repo: "terraform"}
+	releases := fake.New().Add(repo, fake.Release{Tag: "v1.9.0"})
+	p, err := provider.NewURLTemplate(releases, http.DefaultClient,
+		config.Template{
+			URL:         "https://releases.hashicorp.com/{{.Repo}}/{{.Version}}/{{.Name}}_{{.Version}}_{{.OS}}_{{.Arch}}.zip",
+			ChecksumURL: "https://releases.hashicorp.com/{{.Repo}}/{{.Version}}/{{.Name}}_{{.Version}}_SHA256SUMS",
+		}, github.Asset{
+			FileName:        github.FileName{BaseName: "terraform"},
+			Architecture:    github.ArchX86,
+			OperatingSystem: github.OSWindows,
+		})
+	require.NoError(t, err)
+
+	rel, err := p.LatestRelease(ctx, repo)
+	require.NoError(t, err)
+	assert.True(t, rel.Targeted)
+	assert.Equal(t, []artifact.Asset{{
+		Name:        "terraform_1.9.0_windows_386.zip",
+		ContentType: "application/octet-stream",
+		URL:         "https://releases.hashicorp.com/terraform/1.9.0/terraform_1.9.0_windows_386.zip",
+	}, {
+		Name: "checksums.txt",
+		URL:  "https://releases.hashicorp.com/terraform/1.9.0/terraform_1.9.0_SHA256SUMS",
+	}}, rel.Assets)
+
+	_, err = provider.NewURLTemplate(releases, http.DefaultClient,
+		config.Template{URL: "https://example.com/{{.Tag"}, github.Asset{})
+	require.ErrorIs(t, err, provider.ErrInvalidTemplate)
+	p, err = provider.NewURLTemplate(releases, http.DefaultClient,
+		config.Template{URL: "https://example.com/{{.Commit}}"}, github.Asset{})
+	require.NoError(t, err)
+	_, err = p.LatestRelease(ctx, repo)
+	require.ErrorIs(t, err, provider.ErrInvalidTemplate)
+}
+
+func TestURLTemplateDiscoversTags(t *testing.T) {
+	t.Parallel()
+	ctx := context.TestContext(t)
+	repo := artifact.Repository{Owner: "kubernetes", Repo: "kubernetes"}
+	site := config.Site{
+		Type:     config.TypeURL,
+		Address:  "dl.k8s.io",
+		Template: &config.Template{URL: "https://dl.k8s.io/release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/{{.Name}}"},
+	}
+	target := github.Asset{
+		FileName:        github.FileName{BaseName: "kubectl"},
+		Architecture:    github.ArchAMD64,
+		OperatingSystem: github.OSLinuxGnu,
+	}
+	ghapi.WithTestClient(t, func(client *gh.Client, mux *http.ServeMux) {
+		ctx = ghapi.WithContext(ctx, client)
+		mux.HandleFunc("/repos/kubernetes/kubernetes/tags", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"name": "v1.29.6"}, {"name": "kubernetes-ci"}]`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%srepos/kubernetes/kubernetes/tags?page=2>; rel="next"`,
+				client.BaseURL))
+			_, _ = w.Write([]byte(`[{"name": "v1.31.0-rc.0"}, {"name": "v1.30.2"}]`))
+		})
+		mux.HandleFunc("/repos/kubernetes/kubernetes/git/ref/tags/v1.29.6",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"ref": "refs/tags/v1.29.6"}`))
+			})
+
+		p, err := provider.ForSite(ctx, site, target)
+		require.NoError(t, err)
+
+		rel, err := p.LatestRelease(ctx, repo)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.30.2", rel.Tag)
+		require.Len(t, rel.Assets, 1)
+		assert.Equal(t, "https://dl.k8s.io/release/v1.30.2/bin/linux/amd64/kubectl",
+			rel.Assets[0].URL)
+
+		rels, err := p.ListReleases(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, rels, 4)
+		assert.True(t, rels[0].PreRelease)
+
+		rel, err = p.ReleaseByTag(ctx, repo, "v1.29.6")
+		require.NoError(t, err)
+		assert.Equal(t, "v1.29.6", rel.Tag)
+		_, err = p.ReleaseByTag(ctx, repo, "v9.9.9")
+		require.ErrorIs(t, err, provider.ErrNotFound)
+	})
+}
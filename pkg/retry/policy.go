@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultAttempts is the default number of retries of a failed request.
+	DefaultAttempts = 3
+	// DefaultMaxWait is the default longest time to wait before a retry.
+	DefaultMaxWait = time.Minute
+
+	baseDelay = 500 * time.Millisecond
+)
+
+// Policy controls how the failed requests are retried.
+type Policy struct {
+	// Attempts is the number of retries of a failed request. Zero disables
+	// the retries.
+	Attempts int
+	// MaxWait is the longest time to wait before a retry. Rate limits
+	// resetting, or servers asking to retry, later than that aren't waited
+	// for.
+	MaxWait time.Duration
+}
+
+// DefaultPolicy returns the policy used when nothing is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		Attempts: DefaultAttempts,
+		MaxWait:  DefaultMaxWait,
+	}
+}
+
+// backoff returns the exponential delay before the given retry, with a
+// jitter, so concurrent clients don't retry all at once.
+func (p Policy) backoff(attempt int) time.Duration {
+	d := baseDelay << attempt
+	if d <= 0 || d > p.MaxWait {
+		d = p.MaxWait
+	}
+	half := int64(d / 2) //nolint:gomnd
+	if half <= 0 {
+		return d
+	}
+	return time.Duration(half + rand.Int63n(half)) //nolint:gosec
+}
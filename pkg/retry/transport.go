@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// ErrRateLimited is returned when the rate limit is exceeded, and it resets
+// later than the policy allows to wait, or when the server asks to retry later
+// than that.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Transport retries the failed idempotent requests according to the policy.
+// The server errors and the network failures are retried with exponential
+// backoff, while the rate limits are waited for until they reset.
+type Transport struct {
+	Base http.RoundTripper
+	Policy
+	// OnRetry is called before waiting for a retry, if set.
+	OnRetry func(req *http.Request, wait time.Duration, reason string)
+}
+
+// NewTransport wraps the base transport with the retries.
+func NewTransport(base http.RoundTripper, policy Policy) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Policy: policy}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+		wait, reason, rerr := t.decide(resp, err, attempt)
+		if rerr != nil {
+			drain(resp)
+			return nil, rerr
+		}
+		if reason == "" || !t.retryable(req, attempt) {
+			return resp, err //nolint:wrapcheck
+		}
+		drain(resp)
+		if t.OnRetry != nil {
+			t.OnRetry(req, wait, reason)
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, errors.WithStack(req.Context().Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (t *Transport) retryable(req *http.Request, attempt int) bool {
+	if attempt >= t.Attempts || req.Context().Err() != nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody
+	}
+	return false
+}
+
+// decide returns how long to wait before a retry, and why. An empty reason
+// means the request shouldn't be retried.
+func (t *Transport) decide(resp *http.Response, err error, attempt int) (time.Duration, string, error) {
+	if err != nil {
+		return t.backoff(attempt), err.Error(), nil
+	}
+	if reset, ok := t.rateLimitReset(resp); ok {
+		wait := reset.Sub(time.Now())
+		if wait < 0 {
+			wait = 0
+		}
+		if wait > t.MaxWait {
+			return 0, "", errors.WithStack(fmt.Errorf(
+				"%w: resets at %s (in %s), configure a token to raise the limit",
+				ErrRateLimited, reset.Local().Format(time.RFC1123),
+				wait.Round(time.Second)))
+		}
+		return wait, "rate limit exceeded", nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		wait := t.backoff(attempt)
+		if after, ok := t.retryAfter(resp); ok {
+			wait = after
+		}
+		if wait > t.MaxWait {
+			return 0, "", errors.WithStack(fmt.Errorf(
+				"%w: %s asks to retry in %s", ErrRateLimited,
+				resp.Status, wait.Round(time.Second)))
+		}
+		return wait, resp.Status, nil
+	}
+	return 0, "", nil
+}
+
+// rateLimitReset returns the time the exceeded rate limit resets at.
+func (t *Transport) rateLimitReset(resp *http.Response) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden &&
+		resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if after, ok := t.retryAfter(resp); ok {
+		return time.Now().Add(after), true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(secs, 0), true
+}
+
+func (t *Transport) retryAfter(resp *http.Response) (time.Duration, bool) {
+	val := resp.Header.Get("Retry-After")
+	if val == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(val); err == nil {
+		if after := at.Sub(time.Now()); after > 0 {
+			return after, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// drain lets the connection be reused.
+func drain(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
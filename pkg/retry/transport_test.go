@@ -0,0 +1,173 @@
+package retry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cardil/ghet/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+	tcs := []transportTestCase{{
+		name:         "server errors",
+		failures:     2,
+		failure:      badGateway,
+		wantStatus:   http.StatusOK,
+		wantRequests: 3,
+	}, {
+		name:         "retries exhausted",
+		failures:     5,
+		failure:      badGateway,
+		wantStatus:   http.StatusBadGateway,
+		wantRequests: 4,
+	}, {
+		name:         "rate limit reset",
+		failures:     1,
+		failure:      rateLimited(time.Now().Add(-time.Second)),
+		wantStatus:   http.StatusOK,
+		wantRequests: 2,
+	}, {
+		name:         "retry after",
+		failures:     1,
+		failure:      retryAfter("0"),
+		wantStatus:   http.StatusOK,
+		wantRequests: 2,
+	}, {
+		name:         "rate limit resets too late",
+		failures:     1,
+		failure:      rateLimited(time.Now().Add(time.Hour)),
+		wantErr:      retry.ErrRateLimited,
+		wantMessage:  "configure a token",
+		wantRequests: 1,
+	}, {
+		name:         "unavailable too long",
+		failures:     1,
+		failure:      unavailable("3600"),
+		wantErr:      retry.ErrRateLimited,
+		wantMessage:  "503 Service Unavailable asks to retry in 1h0m0s",
+		wantRequests: 1,
+	}, {
+		name:         "unavailable",
+		failures:     1,
+		failure:      unavailable("0"),
+		wantStatus:   http.StatusOK,
+		wantRequests: 2,
+	}, {
+		name:         "not idempotent",
+		method:       http.MethodPost,
+		failures:     1,
+		failure:      badGateway,
+		wantStatus:   http.StatusBadGateway,
+		wantRequests: 1,
+	}, {
+		name:         "not found",
+		failures:     1,
+		failure:      notFound,
+		wantStatus:   http.StatusNotFound,
+		wantRequests: 1,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func (tc transportTestCase) run(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if int(atomic.AddInt32(&requests, 1)) <= tc.failures {
+			tc.failure(w)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	tr := retry.NewTransport(nil, retry.Policy{
+		Attempts: 3,
+		MaxWait:  10 * time.Millisecond,
+	})
+	method := tc.method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if tc.wantErr != nil {
+		require.ErrorIs(t, err, tc.wantErr)
+		assert.Contains(t, err.Error(), tc.wantMessage)
+	} else {
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, tc.wantStatus, resp.StatusCode)
+	}
+	assert.Equal(t, tc.wantRequests, int(atomic.LoadInt32(&requests)))
+}
+
+func TestTransportCancel(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		retryAfter("60")(w)
+	}))
+	defer srv.Close()
+	tr := retry.NewTransport(nil, retry.Policy{Attempts: 3, MaxWait: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = (&http.Client{Transport: tr}).Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func badGateway(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func rateLimited(reset time.Time) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(strings.Repeat("rate limit exceeded ", 10)))
+	}
+}
+
+func retryAfter(secs string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", secs)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+}
+
+func unavailable(secs string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", secs)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+type transportTestCase struct {
+	name         string
+	method       string
+	failures     int
+	failure      func(w http.ResponseWriter)
+	wantStatus   int
+	wantErr      error
+	wantMessage  string
+	wantRequests int
+}